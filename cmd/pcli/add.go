@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ezeqielle/pcli/internal/detect"
+	"github.com/ezeqielle/pcli/internal/postplugin"
+	"github.com/ezeqielle/pcli/internal/ui"
+)
+
+// runAdd applies post-create plugins to an existing project.
+//
+//	pcli add [plugin] [--dir .] [--type go] [--dry-run] [--answers file] [--set plugin.id=value]
+func runAdd(args []string) error {
+	// The plugin may come before the flags: pcli add global --dir ../svc
+	var named string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		named, args = args[0], args[1:]
+	}
+
+	flags := flag.NewFlagSet("pcli add", flag.ContinueOnError)
+	dir := flags.String("dir", ".", "project directory")
+	projectType := flags.String("type", "", "project type (detected from the directory when empty)")
+	dryRun := flags.Bool("dry-run", false, "show the files, folders and commands that would be created without touching disk")
+	answerOpts := addAnswerFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if named == "" && flags.NArg() == 1 {
+		named = flags.Arg(0)
+	} else if flags.NArg() > 0 {
+		return fmt.Errorf("usage: pcli add [plugin] [--dir .] [--type type] [--dry-run]")
+	}
+
+	projectDir, err := filepath.Abs(*dir)
+	if err != nil {
+		return err
+	}
+
+	s, err := newSession(*dryRun)
+	if err != nil {
+		return err
+	}
+
+	if *projectType == "" {
+		*projectType, err = detect.ProjectType(s.fs, projectDir)
+		if err != nil {
+			return err
+		}
+	}
+
+	selected := postplugin.For(*projectType)
+	if named != "" {
+		p, ok := postplugin.Get(named)
+		if !ok {
+			return fmt.Errorf("unknown post-create plugin %q", named)
+		}
+		if !p.Metadata().Supports(*projectType) {
+			return fmt.Errorf("post-create plugin %q does not support %s projects", p.ID(), *projectType)
+		}
+		selected = []postplugin.Plugin{p}
+	}
+	if len(selected) == 0 {
+		return fmt.Errorf("no post-create plugins registered for %s projects", *projectType)
+	}
+
+	s.journal.SetProject(*projectType, "", projectDir)
+
+	if answerOpts.enabled() {
+		answers, err := answerOpts.load()
+		if err != nil {
+			return err
+		}
+		if err := checkPostCreateAnswers(selected, answers); err != nil {
+			return err
+		}
+		if err := applyNonInteractive(s, projectDir, *projectType, selected, answers); err != nil {
+			return err
+		}
+		return s.finish()
+	}
+
+	chain := postplugin.NewChain(projectDir, *projectType, selected)
+	prog := tea.NewProgram(ui.NewNavigator("pcli add", chain))
+	if _, err := prog.Run(); err != nil {
+		return err
+	}
+
+	return s.finish()
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ezeqielle/pcli/internal/postplugin"
+	"github.com/ezeqielle/pcli/internal/projecttype"
+	"github.com/ezeqielle/pcli/internal/question"
+)
+
+// answersFile is the answers of a non-interactive run, as read from
+// --answers / --answers-json and completed by --set:
+//
+//	{
+//	  "type": "go",
+//	  "answers": {"module_path": "github.com/me/svc"},
+//	  "post_create": {"global": {"items": ["global_readme"], "policy": "merge"}}
+//	}
+type answersFile struct {
+	Type       string                      `json:"type,omitempty"`
+	Answers    question.Answers            `json:"answers,omitempty"`
+	PostCreate map[string]question.Answers `json:"post_create,omitempty"`
+}
+
+// setFlags collects repeated --set id=value flags.
+type setFlags []string
+
+func (s *setFlags) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *setFlags) Set(v string) error {
+	if !strings.Contains(v, "=") {
+		return fmt.Errorf("want id=value or plugin.id=value, got %q", v)
+	}
+	*s = append(*s, v)
+	return nil
+}
+
+// answerFlags are the non-interactive flags shared by pcli and pcli add.
+type answerFlags struct {
+	file           string
+	inline         string
+	sets           setFlags
+	nonInteractive bool
+}
+
+func addAnswerFlags(flags *flag.FlagSet) *answerFlags {
+	a := &answerFlags{}
+	flags.StringVar(&a.file, "answers", "", "read answers from a JSON file (- for stdin) and run without the UI")
+	flags.StringVar(&a.inline, "answers-json", "", "answers as a JSON document; run without the UI")
+	flags.Var(&a.sets, "set", "answer a question: id=value for the project type, plugin.id=value for a post-create plugin (repeatable)")
+	flags.BoolVar(&a.nonInteractive, "non-interactive", false, "run without the UI, using defaults for unanswered questions")
+	return a
+}
+
+// enabled reports whether the run should skip the UI.
+func (a *answerFlags) enabled() bool {
+	return a.nonInteractive || a.file != "" || a.inline != "" || len(a.sets) > 0
+}
+
+// load merges the answers file, the inline JSON and the --set flags, in
+// that order of precedence (later wins).
+func (a *answerFlags) load() (*answersFile, error) {
+	out := &answersFile{Answers: question.Answers{}, PostCreate: map[string]question.Answers{}}
+
+	var docs [][]byte
+	if a.file != "" {
+		var data []byte
+		var err error
+		if a.file == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(a.file)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read answers: %w", err)
+		}
+		docs = append(docs, data)
+	}
+	if a.inline != "" {
+		docs = append(docs, []byte(a.inline))
+	}
+
+	for _, data := range docs {
+		var doc answersFile
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse answers: %w", err)
+		}
+		out.merge(doc)
+	}
+
+	for _, set := range a.sets {
+		key, value, _ := strings.Cut(set, "=")
+		if i := strings.LastIndex(key, "."); i >= 0 {
+			out.postCreate(key[:i])[key[i+1:]] = value
+			continue
+		}
+		out.Answers[key] = value
+	}
+
+	return out, nil
+}
+
+func (f *answersFile) merge(doc answersFile) {
+	if doc.Type != "" {
+		f.Type = doc.Type
+	}
+	for id, v := range doc.Answers {
+		f.Answers[id] = v
+	}
+	for plugin, answers := range doc.PostCreate {
+		for id, v := range answers {
+			f.postCreate(plugin)[id] = v
+		}
+	}
+}
+
+func (f *answersFile) postCreate(plugin string) question.Answers {
+	if f.PostCreate[plugin] == nil {
+		f.PostCreate[plugin] = question.Answers{}
+	}
+	return f.PostCreate[plugin]
+}
+
+// createNonInteractive creates a project of the given type from answers,
+// then runs its post-create plugins.
+func createNonInteractive(s *session, typeID string, answers *answersFile) error {
+	p, ok := projecttype.Get(typeID)
+	if !ok {
+		return fmt.Errorf("unknown project type %q", typeID)
+	}
+	d, ok := p.(projecttype.Declarative)
+	if !ok {
+		return fmt.Errorf("project type %q has no declarative questions; run pcli interactively", typeID)
+	}
+
+	plugins := postplugin.For(typeID)
+	if err := checkPostCreateAnswers(plugins, answers); err != nil {
+		return err
+	}
+
+	qs, err := d.Questions()
+	if err != nil {
+		return err
+	}
+	resolved, err := question.Resolve(qs, answers.Answers)
+	if err != nil {
+		return fmt.Errorf("%s: %w", typeID, err)
+	}
+
+	dir, err := d.Create(resolved)
+	if err != nil {
+		return rollbackAfter(s, err)
+	}
+	fmt.Printf("Created %s project in %s\n", p.DisplayName(), dir)
+
+	return applyNonInteractive(s, dir, typeID, plugins, answers)
+}
+
+// applyNonInteractive runs post-create plugins with resolved answers.
+func applyNonInteractive(s *session, dir, typeID string, plugins []postplugin.Plugin, answers *answersFile) error {
+	for _, p := range plugins {
+		d, ok := p.(postplugin.Declarative)
+		if !ok {
+			fmt.Printf("[%s] skipped: no declarative questions\n", p.ID())
+			continue
+		}
+
+		qs, err := d.Questions(dir, typeID)
+		if err != nil {
+			return rollbackAfter(s, fmt.Errorf("%s: %w", p.ID(), err))
+		}
+		resolved, err := question.Resolve(qs, answers.PostCreate[p.ID()])
+		if err != nil {
+			return rollbackAfter(s, fmt.Errorf("%s: %w", p.ID(), err))
+		}
+
+		summary, err := d.Apply(dir, typeID, resolved)
+		for _, line := range summary {
+			fmt.Printf("[%s] %s\n", p.ID(), line)
+		}
+		if err != nil {
+			return rollbackAfter(s, fmt.Errorf("%s: %w", p.ID(), err))
+		}
+	}
+
+	return nil
+}
+
+// checkPostCreateAnswers rejects answers for plugins that will not run.
+func checkPostCreateAnswers(plugins []postplugin.Plugin, answers *answersFile) error {
+	running := map[string]bool{}
+	for _, p := range plugins {
+		running[p.ID()] = true
+	}
+
+	var unknown []string
+	for id := range answers.PostCreate {
+		if !running[id] {
+			unknown = append(unknown, id)
+		}
+	}
+	sort.Strings(unknown)
+
+	if len(unknown) > 0 {
+		return fmt.Errorf("answers given for post-create plugins that do not run here: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// rollbackAfter undoes a failed non-interactive run, since there is nobody
+// to ask.
+func rollbackAfter(s *session, err error) error {
+	if !s.journal.HasChanges() {
+		return err
+	}
+	if _, rbErr := s.journal.Rollback(); rbErr != nil {
+		return errors.Join(err, fmt.Errorf("rollback incomplete: %w", rbErr))
+	}
+	return fmt.Errorf("%w (rolled back)", err)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/history"
+)
+
+func runHistory(args []string) error {
+	flags := flag.NewFlagSet("pcli history", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	store, err := history.DefaultStore()
+	if err != nil {
+		return err
+	}
+
+	records, err := store.Load()
+	if err != nil {
+		return err
+	}
+
+	if len(records) == 0 {
+		fmt.Println("No projects created yet.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tCREATED\tTYPE\tMODULE\tDIR\tPLUGINS\tFILES\tVERSION\tSTATUS")
+	for _, r := range records {
+		status := "active"
+		if r.UndoneAt != nil {
+			status = "undone " + r.UndoneAt.Format(time.DateTime)
+		}
+		fmt.Fprintf(
+			w,
+			"%d\t%s\t%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+			r.ID,
+			r.Timestamp.Format(time.DateTime),
+			r.ProjectType,
+			r.Module,
+			r.Dir,
+			strings.Join(r.Plugins, ","),
+			len(r.Files),
+			r.Version,
+			status,
+		)
+	}
+	return w.Flush()
+}
+
+func runUndo(args []string) error {
+	flags := flag.NewFlagSet("pcli undo", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: pcli undo <id>")
+	}
+
+	id, err := strconv.Atoi(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("invalid history id %q", flags.Arg(0))
+	}
+
+	store, err := history.DefaultStore()
+	if err != nil {
+		return err
+	}
+
+	summary, err := store.Undo(fsys.NewOS(), id)
+	for _, line := range summary {
+		fmt.Println("- " + line)
+	}
+	return err
+}
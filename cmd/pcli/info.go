@@ -0,0 +1,87 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/ezeqielle/pcli/internal/detect"
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/postplugin"
+	"github.com/ezeqielle/pcli/internal/runner"
+)
+
+// runInfo reports the detected project type(s) of a directory and which
+// post-create items are already present.
+//
+//	pcli info [--dir .]
+func runInfo(args []string) error {
+	flags := flag.NewFlagSet("pcli info", flag.ContinueOnError)
+	dir := flags.String("dir", ".", "project directory")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	projectDir, err := filepath.Abs(*dir)
+	if err != nil {
+		return err
+	}
+
+	fs := fsys.NewOS()
+	if err := registerPlugins(runner.NewExec(), fs); err != nil {
+		return err
+	}
+
+	results, err := detect.All(fs, projectDir)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Project: " + projectDir)
+	fmt.Println()
+
+	if len(results) == 0 {
+		fmt.Println("No known project type detected.")
+		return nil
+	}
+
+	fmt.Println("Detected types:")
+	for _, r := range results {
+		fmt.Printf("  %s (confidence %.0f%%)\n", r.Type, r.Confidence*100)
+
+		keys := make([]string, 0, len(r.Metadata))
+		for k := range r.Metadata {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Printf("    %s: %s\n", k, r.Metadata[k])
+		}
+	}
+
+	projectType := results[0].Type
+	for _, p := range postplugin.For(projectType) {
+		in, ok := p.(postplugin.Inspector)
+		if !ok {
+			continue
+		}
+
+		fmt.Printf("\nPost-create items – %s:\n", p.DisplayName())
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, st := range in.Inspect(projectDir, projectType) {
+			state := "missing"
+			if st.Present {
+				state = "present"
+			}
+			fmt.Fprintf(w, "  %s\t%s\t%s\n", state, st.Path, st.ID)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,31 +1,189 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log"
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/history"
+	"github.com/ezeqielle/pcli/internal/journal"
+	"github.com/ezeqielle/pcli/internal/plan"
 	"github.com/ezeqielle/pcli/internal/plugins"
-	"github.com/ezeqielle/pcli/internal/postplugin"
+	"github.com/ezeqielle/pcli/internal/runner"
+	"github.com/ezeqielle/pcli/internal/settings"
 	"github.com/ezeqielle/pcli/internal/ui"
+	"github.com/ezeqielle/pcli/internal/version"
 )
 
 func main() {
-	if err := run(); err != nil {
+	if err := run(os.Args[1:]); err != nil {
 		log.Println("pcli exited with error:", err)
 		os.Exit(1)
 	}
 }
 
-func run() error {
-	plugins.RegisterAll()
+func run(args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "add":
+			return runAdd(args[1:])
+		case "history":
+			return runHistory(args[1:])
+		case "info":
+			return runInfo(args[1:])
+		case "plugins":
+			return runPlugins(args[1:])
+		case "questions":
+			return runQuestions(args[1:])
+		case "undo":
+			return runUndo(args[1:])
+		}
+	}
+
+	return runCreate(args)
+}
+
+func runCreate(args []string) error {
+	flags := flag.NewFlagSet("pcli", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "show the files, folders and commands that would be created without touching disk")
+	projectType := flags.String("type", "", "project type to create without the UI (or \"type\" in the answers)")
+	answerOpts := addAnswerFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	s, err := newSession(*dryRun)
+	if err != nil {
+		return err
+	}
+
+	if *projectType != "" || answerOpts.enabled() {
+		answers, err := answerOpts.load()
+		if err != nil {
+			return err
+		}
+		if *projectType != "" {
+			answers.Type = *projectType
+		}
+		if answers.Type == "" {
+			return fmt.Errorf("non-interactive runs need --type or \"type\" in the answers")
+		}
+		if err := createNonInteractive(s, answers.Type, answers); err != nil {
+			return err
+		}
+		return s.finish()
+	}
+
+	chooser := ui.NewTypeChooserModel(s.runner, recentTypes())
+	prog := tea.NewProgram(ui.NewNavigator("pcli", chooser))
+
+	if _, err := prog.Run(); err != nil {
+		return err
+	}
+
+	return s.finish()
+}
 
-	postplugin.RegisterAll()
+// recentTypes returns the project types of the latest runs from the
+// history; a missing or unreadable history pins nothing.
+func recentTypes() []string {
+	store, err := history.DefaultStore()
+	if err != nil {
+		return nil
+	}
+	types, err := store.RecentTypes(3)
+	if err != nil {
+		return nil
+	}
+	return types
+}
+
+// session wires the runner and filesystem every plugin writes through:
+// optionally dry-run, always journaled.
+type session struct {
+	runner  runner.Runner
+	fs      fsys.FS
+	journal *journal.Journal
+	plan    *plan.Plan
+}
+
+func newSession(dryRun bool) (*session, error) {
+	var r runner.Runner = runner.NewExec()
+	var fs fsys.FS = fsys.NewOS()
+
+	var p *plan.Plan
+	if dryRun {
+		p = plan.New()
+		r = runner.NewDryRun(r, p)
+		fs = fsys.NewDryRun(fs, p)
+	}
 
-	m := ui.NewTypeChooserModel()
-	p := tea.NewProgram(m)
+	// Journal every change so a failed or cancelled run can be rolled back.
+	j := journal.New(fs)
+	fs = j.FS()
+	r = j.Runner(r)
 
-	_, err := p.Run()
-	return err
+	if err := registerPlugins(r, fs); err != nil {
+		return nil, err
+	}
+
+	return &session{runner: r, fs: fs, journal: j, plan: p}, nil
+}
+
+// registerPlugins loads the pcli configuration and registers every plugin
+// with it. Plugins that fail to load only produce a warning; an invalid
+// configuration is an error.
+func registerPlugins(r runner.Runner, fs fsys.FS) error {
+	cfg, err := settings.LoadDefault(fsys.NewOS())
+	if err != nil {
+		return err
+	}
+	if err := ui.ApplyTheme(cfg.UI); err != nil {
+		log.Println("warning: ui:", err)
+	}
+
+	// Read-only queries bypass the journal and the dry run.
+	if err := plugins.RegisterAll(r, runner.NewExec(), fs, cfg); err != nil {
+		log.Println("warning: some plugins could not be loaded:", err)
+	}
+	return nil
+}
+
+// finish prints the plan of a dry run, or records a real run in the history.
+func (s *session) finish() error {
+	if s.plan != nil {
+		fmt.Println("\nDry run – nothing was written. Plan:")
+		fmt.Println()
+		fmt.Print(s.plan.Render())
+		return nil
+	}
+
+	return recordHistory(s.journal, s.fs)
+}
+
+// recordHistory appends the completed run to the creation history.
+// Runs that created nothing, or were rolled back, are not recorded.
+func recordHistory(j *journal.Journal, fs fsys.FS) error {
+	if !j.HasChanges() || j.Info().Dir == "" {
+		return nil
+	}
+
+	rec, err := history.FromJournal(j, fs, version.Version)
+	if err != nil {
+		return fmt.Errorf("failed to record history: %w", err)
+	}
+
+	store, err := history.DefaultStore()
+	if err != nil {
+		return fmt.Errorf("failed to record history: %w", err)
+	}
+
+	if _, err := store.Add(rec); err != nil {
+		return fmt.Errorf("failed to record history: %w", err)
+	}
+	return nil
 }
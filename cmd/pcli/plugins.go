@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/pluginmeta"
+	"github.com/ezeqielle/pcli/internal/postplugin"
+	"github.com/ezeqielle/pcli/internal/projecttype"
+	"github.com/ezeqielle/pcli/internal/runner"
+)
+
+// pluginInfo is one row of `pcli plugins list`.
+type pluginInfo struct {
+	Kind        string `json:"kind"`
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Source is "builtin" or the path of an external plugin.
+	Source string `json:"source"`
+	// Enabled is false for plugins the configuration turns off.
+	Enabled bool `json:"enabled"`
+
+	pluginmeta.Metadata
+}
+
+// runPlugins handles the plugin management subcommands.
+//
+//	pcli plugins list [--json]
+func runPlugins(args []string) error {
+	if len(args) == 0 || args[0] != "list" {
+		return fmt.Errorf("usage: pcli plugins list [--json]")
+	}
+
+	flags := flag.NewFlagSet("pcli plugins list", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print the plugins as JSON")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if err := registerPlugins(runner.NewExec(), fsys.NewOS()); err != nil {
+		return err
+	}
+
+	enabled := map[string]bool{}
+	for _, p := range projecttype.Enabled() {
+		enabled["project-type/"+p.ID()] = true
+	}
+	for _, p := range postplugin.Enabled() {
+		enabled["post-create/"+p.ID()] = true
+	}
+
+	var infos []pluginInfo
+	for _, p := range projecttype.All() {
+		infos = append(infos, pluginInfo{
+			Kind:        "project-type",
+			ID:          p.ID(),
+			Name:        p.DisplayName(),
+			Description: p.Description(),
+			Source:      pluginSource(p),
+			Enabled:     enabled["project-type/"+p.ID()],
+			Metadata:    p.Metadata(),
+		})
+	}
+	for _, p := range postplugin.All() {
+		info := pluginInfo{
+			Kind:     "post-create",
+			ID:       p.ID(),
+			Name:     p.DisplayName(),
+			Source:   pluginSource(p),
+			Enabled:  enabled["post-create/"+p.ID()],
+			Metadata: p.Metadata(),
+		}
+		if d, ok := p.(interface{ Description() string }); ok {
+			info.Description = d.Description()
+		}
+		infos = append(infos, info)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if infos == nil {
+			infos = []pluginInfo{}
+		}
+		return enc.Encode(infos)
+	}
+
+	if len(infos) == 0 {
+		fmt.Println("No plugins registered.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tID\tNAME\tVERSION\tTYPES\tTAGS\tREQUIRES\tENABLED\tSOURCE")
+	for _, info := range infos {
+		state := "yes"
+		if !info.Enabled {
+			state = "no"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			info.Kind,
+			info.ID,
+			info.Name,
+			orDash(info.Version),
+			orDash(strings.Join(info.ProjectTypes, ",")),
+			orDash(strings.Join(info.Tags, ",")),
+			orDash(strings.Join(info.RequiredTools, ",")),
+			state,
+			info.Source,
+		)
+	}
+	return w.Flush()
+}
+
+// pluginSource returns the executable path of external plugins and
+// "builtin" for everything else.
+func pluginSource(p any) string {
+	if ext, ok := p.(interface{ Path() string }); ok {
+		return ext.Path()
+	}
+	return "builtin"
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/postplugin"
+	"github.com/ezeqielle/pcli/internal/projecttype"
+	"github.com/ezeqielle/pcli/internal/question"
+	"github.com/ezeqielle/pcli/internal/runner"
+)
+
+// questionsDoc mirrors answersFile with questions instead of answers.
+type questionsDoc struct {
+	Type       string                         `json:"type"`
+	Questions  []question.Question            `json:"questions"`
+	PostCreate map[string][]question.Question `json:"post_create,omitempty"`
+}
+
+// runQuestions prints the declarative questions of a project type and its
+// post-create plugins as JSON, to help write answers files. With --dir, the
+// post-create plugins pick their defaults from that existing project.
+//
+//	pcli questions <type> [--dir path]
+func runQuestions(args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return fmt.Errorf("usage: pcli questions <type> [--dir path]")
+	}
+	typeID, args := args[0], args[1:]
+
+	flags := flag.NewFlagSet("pcli questions", flag.ContinueOnError)
+	dir := flags.String("dir", "", "existing project the post-create defaults are read from")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("usage: pcli questions <type> [--dir path]")
+	}
+
+	projectDir := ""
+	if *dir != "" {
+		abs, err := filepath.Abs(*dir)
+		if err != nil {
+			return err
+		}
+		projectDir = abs
+	}
+
+	if err := registerPlugins(runner.NewExec(), fsys.NewOS()); err != nil {
+		return err
+	}
+
+	p, ok := projecttype.Get(typeID)
+	if !ok {
+		return fmt.Errorf("unknown project type %q", typeID)
+	}
+	d, ok := p.(projecttype.Declarative)
+	if !ok {
+		return fmt.Errorf("project type %q has no declarative questions", typeID)
+	}
+
+	qs, err := d.Questions()
+	if err != nil {
+		return err
+	}
+	doc := questionsDoc{Type: typeID, Questions: qs, PostCreate: map[string][]question.Question{}}
+
+	for _, pp := range postplugin.For(typeID) {
+		pd, ok := pp.(postplugin.Declarative)
+		if !ok {
+			continue
+		}
+		qs, err := pd.Questions(projectDir, typeID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", pp.ID(), err)
+		}
+		doc.PostCreate[pp.ID()] = qs
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
@@ -0,0 +1,96 @@
+// Command pcli-plugin-hello is a minimal external post-create plugin.
+//
+// Build it and put it in a plugin directory or on PATH:
+//
+//	go build -o ~/.local/share/pcli/plugins/pcli-plugin-hello ./examples/pcli-plugin-hello
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+type request struct {
+	ID     int             `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+type answersParams struct {
+	ProjectPath string         `json:"project_path"`
+	ProjectType string         `json:"project_type"`
+	Answers     map[string]any `json:"answers"`
+}
+
+func main() {
+	var req request
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		fmt.Fprintln(os.Stderr, "invalid request:", err)
+		os.Exit(1)
+	}
+
+	result, err := handle(req)
+	resp := map[string]any{"jsonrpc": "2.0", "id": req.ID}
+	if err != nil {
+		resp["error"] = map[string]any{"code": 1, "message": err.Error()}
+	} else {
+		resp["result"] = result
+	}
+
+	if err := json.NewEncoder(os.Stdout).Encode(resp); err != nil {
+		os.Exit(1)
+	}
+}
+
+func handle(req request) (any, error) {
+	switch req.Method {
+	case "describe":
+		return map[string]any{
+			"protocol_version": 1,
+			"id":               "hello",
+			"name":             "Hello file",
+			"description":      "Writes a HELLO.md greeting",
+			"kind":             "postcreate",
+			"version":          "0.1.0",
+			"tags":             []string{"example"},
+			"after":            []string{"global"},
+		}, nil
+
+	case "questions":
+		return map[string]any{
+			"questions": []map[string]any{
+				{"id": "name", "type": "text", "label": "Who should we greet?", "default": "world", "required": true},
+				{"id": "shout", "type": "confirm", "label": "Shout?", "default": false},
+			},
+		}, nil
+
+	case "validate":
+		var p answersParams
+		if err := json.Unmarshal(req.Params, &p); err != nil {
+			return nil, err
+		}
+		if name, _ := p.Answers["name"].(string); len(name) > 40 {
+			return map[string]any{"errors": map[string]string{"name": "keep it under 40 characters"}}, nil
+		}
+		return map[string]any{}, nil
+
+	case "apply":
+		var p answersParams
+		if err := json.Unmarshal(req.Params, &p); err != nil {
+			return nil, err
+		}
+		greeting := fmt.Sprintf("Hello, %s.", p.Answers["name"])
+		if shout, _ := p.Answers["shout"].(bool); shout {
+			greeting = fmt.Sprintf("HELLO, %s!", p.Answers["name"])
+		}
+		return map[string]any{
+			"files": []map[string]any{
+				{"path": "HELLO.md", "content": "# " + greeting + "\n"},
+			},
+			"messages": []string{"Greeted " + fmt.Sprint(p.Answers["name"])},
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown method %q", req.Method)
+}
@@ -0,0 +1,210 @@
+package conflict
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/ezeqielle/pcli/internal/fsys"
+)
+
+// Policy decides what happens when a file pcli wants to write already exists.
+type Policy int
+
+const (
+	// Skip leaves the existing file untouched.
+	Skip Policy = iota
+	// Overwrite replaces the existing file.
+	Overwrite
+	// Backup copies the existing file to <name>.bak before overwriting it.
+	Backup
+	// Append adds the new content after the existing content.
+	Append
+	// Merge adds only the lines of the new content that are not already present.
+	// Meant for line-based files like .gitignore.
+	Merge
+	// Ask lets the user pick one of the other policies after seeing a diff.
+	Ask
+)
+
+// Policies lists every policy in cycling order.
+var Policies = []Policy{Skip, Overwrite, Backup, Append, Merge, Ask}
+
+func (p Policy) String() string {
+	switch p {
+	case Skip:
+		return "skip"
+	case Overwrite:
+		return "overwrite"
+	case Backup:
+		return "backup"
+	case Append:
+		return "append"
+	case Merge:
+		return "merge"
+	case Ask:
+		return "ask"
+	}
+	return fmt.Sprintf("Policy(%d)", int(p))
+}
+
+// Next returns the policy after p in cycling order.
+func (p Policy) Next() Policy {
+	for i, q := range Policies {
+		if q == p {
+			return Policies[(i+1)%len(Policies)]
+		}
+	}
+	return Skip
+}
+
+// Parse returns the policy named s.
+func Parse(s string) (Policy, error) {
+	for _, p := range Policies {
+		if p.String() == strings.ToLower(strings.TrimSpace(s)) {
+			return p, nil
+		}
+	}
+	return Skip, fmt.Errorf("unknown conflict policy %q (want skip, overwrite, backup, append, merge or ask)", s)
+}
+
+// Write writes data to path, resolving an existing file with policy.
+// It returns a human-readable description of the action taken.
+// Ask cannot be resolved here and is treated as Skip.
+func Write(target fsys.FS, path string, data []byte, perm fs.FileMode, policy Policy) (string, error) {
+	existing, err := target.ReadFile(path)
+	if err != nil {
+		if !fsys.IsNotExist(err) {
+			return "", err
+		}
+		if err := target.WriteFile(path, data, perm); err != nil {
+			return "", err
+		}
+		return "created", nil
+	}
+
+	switch policy {
+	case Overwrite:
+		if bytes.Equal(existing, data) {
+			return "unchanged", nil
+		}
+		if err := target.WriteFile(path, data, perm); err != nil {
+			return "", err
+		}
+		return "overwritten", nil
+
+	case Backup:
+		if bytes.Equal(existing, data) {
+			return "unchanged", nil
+		}
+		backup := backupPath(target, path)
+		if err := target.WriteFile(backup, existing, perm); err != nil {
+			return "", err
+		}
+		if err := target.WriteFile(path, data, perm); err != nil {
+			return "", err
+		}
+		return "backed up to " + backup + " and overwritten", nil
+
+	case Append:
+		if err := target.WriteFile(path, AppendContent(existing, data), perm); err != nil {
+			return "", err
+		}
+		return "appended", nil
+
+	case Merge:
+		merged, added := MergeLines(existing, data)
+		if added == 0 {
+			return "already up to date", nil
+		}
+		if err := target.WriteFile(path, merged, perm); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("merged %d new line(s)", added), nil
+	}
+
+	return "already exists (skipped)", nil
+}
+
+func backupPath(target fsys.FS, path string) string {
+	candidate := path + ".bak"
+	for i := 1; fsys.Exists(target, candidate); i++ {
+		candidate = fmt.Sprintf("%s.bak.%d", path, i)
+	}
+	return candidate
+}
+
+// AppendContent returns existing followed by data, separated by a newline
+// when existing does not already end with one.
+func AppendContent(existing, data []byte) []byte {
+	out := make([]byte, 0, len(existing)+len(data)+1)
+	out = append(out, existing...)
+	if len(existing) > 0 && !bytes.HasSuffix(existing, []byte("\n")) {
+		out = append(out, '\n')
+	}
+	return append(out, data...)
+}
+
+// MergeLines appends the lines of data missing from existing, keeping their
+// order. Blank lines separate blocks in data; a comment line (starting with
+// #) is only added together with a missing line of its block, so sections
+// already present are not repeated. It returns the result and the number of
+// lines added.
+func MergeLines(existing, data []byte) ([]byte, int) {
+	present := map[string]bool{}
+	for _, line := range splitLines(existing) {
+		present[strings.TrimSpace(line)] = true
+	}
+
+	var blocks []string
+	added := 0
+	var block, comments []string
+	flush := func() {
+		if len(block) > 0 {
+			blocks = append(blocks, strings.Join(block, "\n"))
+		}
+		block, comments = nil, nil
+	}
+
+	for _, line := range splitLines(data) {
+		key := strings.TrimSpace(line)
+		switch {
+		case key == "":
+			flush()
+		case present[key]:
+		case strings.HasPrefix(key, "#"):
+			comments = append(comments, line)
+		default:
+			present[key] = true
+			block = append(block, comments...)
+			block = append(block, line)
+			added += len(comments) + 1
+			comments = nil
+		}
+	}
+	flush()
+
+	if added == 0 {
+		return existing, 0
+	}
+
+	out := existing
+	if len(bytes.TrimSpace(existing)) > 0 {
+		// keep the new lines apart from what was there
+		out = AppendContent(existing, nil)
+		if !bytes.HasSuffix(out, []byte("\n\n")) {
+			out = append(out, '\n')
+		}
+	}
+	return append(out, []byte(strings.Join(blocks, "\n\n")+"\n")...), added
+}
+
+func splitLines(data []byte) []string {
+	s := strings.ReplaceAll(string(data), "\r\n", "\n")
+	s = strings.TrimSuffix(s, "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
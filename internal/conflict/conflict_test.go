@@ -0,0 +1,171 @@
+package conflict
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ezeqielle/pcli/internal/fsys"
+)
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string // "" means the file does not exist
+		data     string
+		policy   Policy
+		action   string
+		want     string
+		backup   string
+	}{
+		{"new file", "", "a\n", Skip, "created", "a\n", ""},
+		{"skip", "old\n", "new\n", Skip, "already exists (skipped)", "old\n", ""},
+		{"ask is skip", "old\n", "new\n", Ask, "already exists (skipped)", "old\n", ""},
+		{"overwrite", "old\n", "new\n", Overwrite, "overwritten", "new\n", ""},
+		{"overwrite same", "same\n", "same\n", Overwrite, "unchanged", "same\n", ""},
+		{"backup", "old\n", "new\n", Backup, "backed up to /p/f.bak and overwritten", "new\n", "old\n"},
+		{"append", "old\n", "new\n", Append, "appended", "old\nnew\n", ""},
+		{"append without newline", "old", "new\n", Append, "appended", "old\nnew\n", ""},
+		{"merge", "bin/\n", "bin/\n.env\n", Merge, "merged 1 new line(s)", "bin/\n\n.env\n", ""},
+		{"merge up to date", "bin/\n.env\n", ".env\n", Merge, "already up to date", "bin/\n.env\n", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := fsys.NewMemory()
+			if err := fs.MkdirAll("/p", 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if tt.existing != "" {
+				if err := fs.WriteFile("/p/f", []byte(tt.existing), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			action, err := Write(fs, "/p/f", []byte(tt.data), 0o644, tt.policy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if action != tt.action {
+				t.Errorf("action = %q, want %q", action, tt.action)
+			}
+			if got, _ := fs.ReadFile("/p/f"); string(got) != tt.want {
+				t.Errorf("content = %q, want %q", got, tt.want)
+			}
+			if got, _ := fs.ReadFile("/p/f.bak"); string(got) != tt.backup {
+				t.Errorf("backup = %q, want %q", got, tt.backup)
+			}
+		})
+	}
+}
+
+func TestBackupPath(t *testing.T) {
+	fs := fsys.NewMemory()
+	if err := fs.MkdirAll("/p", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"/p/f", "/p/f.bak"} {
+		if err := fs.WriteFile(path, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	action, err := Write(fs, "/p/f", []byte("y"), 0o644, Backup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "backed up to /p/f.bak.1 and overwritten"; action != want {
+		t.Errorf("action = %q, want %q", action, want)
+	}
+}
+
+func TestMergeLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		data     string
+		want     string
+		added    int
+	}{
+		{"empty file", "", "a\nb\n", "a\nb\n", 2},
+		{"nothing new", "a\nb\n", "b\na\n", "a\nb\n", 0},
+		{"missing line", "a\n", "a\nb\n", "a\n\nb\n", 1},
+		{"keeps blank line", "a\n\n", "b\n", "a\n\nb\n", 1},
+		{"crlf", "a\r\n", "a\nb\n", "a\r\n\nb\n", 1},
+		{"whitespace", "  a  \n", "a\n", "  a  \n", 0},
+		{"comment with new line", "a\n", "# Go\nbin/\n", "a\n\n# Go\nbin/\n", 2},
+		{"comment of present block", "bin/\n", "# Go\nbin/\n", "bin/\n", 0},
+		{"blocks", "", "# A\na\n\n# B\nb\n", "# A\na\n\n# B\nb\n", 4},
+		{"partial block", "a\n", "# A\na\nc\n", "a\n\n# A\nc\n", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, added := MergeLines([]byte(tt.existing), []byte(tt.data))
+			if string(got) != tt.want || added != tt.added {
+				t.Errorf("MergeLines = %q, %d, want %q, %d", got, added, tt.want, tt.added)
+			}
+		})
+	}
+}
+
+func TestAppendContent(t *testing.T) {
+	tests := []struct {
+		existing, data, want string
+	}{
+		{"", "a\n", "a\n"},
+		{"a\n", "b\n", "a\nb\n"},
+		{"a", "b\n", "a\nb\n"},
+		{"a", "", "a\n"},
+	}
+
+	for _, tt := range tests {
+		if got := AppendContent([]byte(tt.existing), []byte(tt.data)); string(got) != tt.want {
+			t.Errorf("AppendContent(%q, %q) = %q, want %q", tt.existing, tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     []string
+	}{
+		{"equal", "a\nb\n", "a\nb\n", []string{"  a", "  b"}},
+		{"both empty", "", "", nil},
+		{"from empty", "", "a\n", []string{"+ a"}},
+		{"to empty", "a\n", "", []string{"- a"}},
+		{"changed line", "a\nb\nc\n", "a\nx\nc\n", []string{"  a", "- b", "+ x", "  c"}},
+		{"inserted", "a\nc\n", "a\nb\nc\n", []string{"  a", "+ b", "  c"}},
+		{"removed", "a\nb\nc\n", "a\nc\n", []string{"  a", "- b", "  c"}},
+		{"moved", "a\nb\nc\n", "b\nc\na\n", []string{"- a", "  b", "  c", "+ a"}},
+		{"crlf", "a\r\nb\r\n", "a\nb\n", []string{"  a", "  b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, l := range Diff([]byte(tt.old), []byte(tt.new)) {
+				got = append(got, l.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, p := range Policies {
+		got, err := Parse(" " + p.String() + " ")
+		if err != nil || got != p {
+			t.Errorf("Parse(%q) = %v, %v", p.String(), got, err)
+		}
+		if p.Next() == p {
+			t.Errorf("%v.Next() is itself", p)
+		}
+	}
+	if _, err := Parse("rename"); err == nil {
+		t.Error("Parse accepted an unknown policy")
+	}
+}
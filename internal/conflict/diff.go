@@ -0,0 +1,64 @@
+package conflict
+
+// DiffOp marks a line as kept, removed or added.
+type DiffOp byte
+
+const (
+	DiffKeep   DiffOp = ' '
+	DiffRemove DiffOp = '-'
+	DiffAdd    DiffOp = '+'
+)
+
+type DiffLine struct {
+	Op   DiffOp
+	Text string
+}
+
+func (l DiffLine) String() string {
+	return string(l.Op) + " " + l.Text
+}
+
+// Diff returns a line diff turning old into new, based on the longest common
+// subsequence. It is meant for the small files pcli scaffolds.
+func Diff(old, new []byte) []DiffLine {
+	a, b := splitLines(old), splitLines(new)
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out []DiffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			out = append(out, DiffLine{Op: DiffKeep, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, DiffLine{Op: DiffRemove, Text: a[i]})
+			i++
+		default:
+			out = append(out, DiffLine{Op: DiffAdd, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		out = append(out, DiffLine{Op: DiffRemove, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		out = append(out, DiffLine{Op: DiffAdd, Text: b[j]})
+	}
+	return out
+}
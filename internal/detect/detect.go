@@ -0,0 +1,180 @@
+package detect
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/projecttype"
+)
+
+// Result is one project type recognised in a directory.
+type Result struct {
+	Type       string
+	Confidence float64
+	Metadata   map[string]string
+}
+
+// builtin detects project types that have no registered plugin yet,
+// from their manifest files.
+type builtin struct {
+	projectType string
+	detect      func(fs fsys.FS, dir string) (float64, map[string]string)
+}
+
+var builtins = []builtin{
+	{projectType: "go", detect: detectGoMod},
+	{projectType: "node", detect: detectPackageJSON},
+	{projectType: "python", detect: detectPyproject},
+	{projectType: "terraform", detect: detectTerraform},
+}
+
+// All returns every project type recognised in dir, most confident first.
+//
+// Registered projecttype plugins implementing projecttype.Detector are asked
+// first; built-in manifest checks cover the types no plugin detected.
+func All(fs fsys.FS, dir string) ([]Result, error) {
+	info, err := fs.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read project directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	var results []Result
+	seen := map[string]bool{}
+
+	for _, p := range projecttype.All() {
+		d, ok := p.(projecttype.Detector)
+		if !ok {
+			continue
+		}
+		seen[p.ID()] = true
+		if conf, meta := d.Detect(dir); conf > 0 {
+			results = append(results, Result{Type: p.ID(), Confidence: conf, Metadata: meta})
+		}
+	}
+
+	for _, b := range builtins {
+		if seen[b.projectType] {
+			continue
+		}
+		if conf, meta := b.detect(fs, dir); conf > 0 {
+			results = append(results, Result{Type: b.projectType, Confidence: conf, Metadata: meta})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Confidence > results[j].Confidence
+	})
+	return results, nil
+}
+
+// ProjectType returns the most likely type of the project in dir.
+func ProjectType(fs fsys.FS, dir string) (string, error) {
+	results, err := All(fs, dir)
+	if err != nil {
+		return "", err
+	}
+	if len(results) == 0 {
+		return "", fmt.Errorf("could not detect the project type of %s (no go.mod, package.json, pyproject.toml or *.tf)", dir)
+	}
+	return results[0].Type, nil
+}
+
+func detectGoMod(fs fsys.FS, dir string) (float64, map[string]string) {
+	if !fsys.Exists(fs, filepath.Join(dir, "go.mod")) {
+		return 0, nil
+	}
+	return 1, map[string]string{}
+}
+
+func detectPackageJSON(fs fsys.FS, dir string) (float64, map[string]string) {
+	data, err := fs.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return 0, nil
+	}
+
+	var pkg struct {
+		Name    string            `json:"name"`
+		Version string            `json:"version"`
+		Engines map[string]string `json:"engines"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return 0.5, map[string]string{}
+	}
+
+	meta := map[string]string{}
+	if pkg.Name != "" {
+		meta["name"] = pkg.Name
+	}
+	if pkg.Version != "" {
+		meta["version"] = pkg.Version
+	}
+	if node := pkg.Engines["node"]; node != "" {
+		meta["node"] = node
+	}
+	return 1, meta
+}
+
+func detectPyproject(fs fsys.FS, dir string) (float64, map[string]string) {
+	data, err := fs.ReadFile(filepath.Join(dir, "pyproject.toml"))
+	if err != nil {
+		return 0, nil
+	}
+
+	// Only the [project] table is read, key by key; full TOML is not needed.
+	meta := map[string]string{}
+	section := ""
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			section = strings.Trim(line, "[] ")
+			continue
+		}
+		if section != "project" {
+			continue
+		}
+		key, val, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		if key != "name" && key != "version" && key != "requires-python" {
+			continue
+		}
+		val = strings.TrimSpace(val)
+		if unquoted, err := strconv.Unquote(val); err == nil {
+			val = unquoted
+		} else {
+			val = strings.Trim(val, `'"`)
+		}
+		meta[key] = val
+	}
+	return 1, meta
+}
+
+func detectTerraform(fs fsys.FS, dir string) (float64, map[string]string) {
+	entries, err := fs.ReadDir(dir)
+	if err != nil {
+		return 0, nil
+	}
+
+	count := 0
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".tf") {
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, nil
+	}
+	return 0.9, map[string]string{"files": strconv.Itoa(count)}
+}
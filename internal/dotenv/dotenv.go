@@ -0,0 +1,352 @@
+// Package dotenv reads env files: KEY=value lines with an optional export
+// prefix, comments, single- and double-quoted values that may span lines,
+// backslash escapes and ${VAR} interpolation. CRLF line endings are
+// accepted.
+package dotenv
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ezeqielle/pcli/internal/fsys"
+)
+
+// Entry is a variable defined in a file.
+type Entry struct {
+	Key   string
+	Value string
+	// Line is where the definition starts.
+	Line int
+}
+
+// SyntaxError reports a malformed entry.
+type SyntaxError struct {
+	File string
+	Line int
+	Msg  string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Msg)
+}
+
+// Lookup resolves variables the file itself does not define, for
+// interpolation; os.LookupEnv is the usual choice.
+type Lookup func(key string) (string, bool)
+
+// Parse reads the entries of data, in order; name is the file name used
+// in errors. ${VAR}, ${VAR:-default} and $VAR in unquoted and
+// double-quoted values expand to earlier entries, then to lookup, then to
+// "". Single-quoted values are taken literally.
+func Parse(data []byte, name string, lookup Lookup) ([]Entry, error) {
+	p := &parser{
+		src:    strings.ReplaceAll(string(data), "\r\n", "\n"),
+		name:   name,
+		line:   1,
+		vars:   map[string]string{},
+		lookup: lookup,
+	}
+	return p.parse()
+}
+
+// Map returns the entries as a map; later definitions win.
+func Map(entries []Entry) map[string]string {
+	out := make(map[string]string, len(entries))
+	for _, e := range entries {
+		out[e.Key] = e.Value
+	}
+	return out
+}
+
+// ReadFile parses the file at path, interpolating from the process
+// environment. A missing file yields an empty map and no error.
+func ReadFile(fs fsys.FS, path string) (map[string]string, error) {
+	data, err := fs.ReadFile(path)
+	if fsys.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	entries, err := Parse(data, path, os.LookupEnv)
+	if err != nil {
+		return nil, err
+	}
+	return Map(entries), nil
+}
+
+type parser struct {
+	src  string
+	pos  int
+	name string
+	line int
+
+	vars   map[string]string
+	lookup Lookup
+}
+
+func (p *parser) errorf(line int, format string, args ...any) error {
+	return &SyntaxError{File: p.name, Line: line, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *parser) peek() byte {
+	return p.src[p.pos]
+}
+
+// next consumes a byte, counting lines.
+func (p *parser) next() byte {
+	c := p.src[p.pos]
+	p.pos++
+	if c == '\n' {
+		p.line++
+	}
+	return c
+}
+
+func (p *parser) skipBlanks() {
+	for !p.eof() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.pos++
+	}
+}
+
+// skipLine consumes the rest of the line, including the newline.
+func (p *parser) skipLine() {
+	for !p.eof() && p.next() != '\n' {
+	}
+}
+
+func (p *parser) parse() ([]Entry, error) {
+	var entries []Entry
+	for !p.eof() {
+		p.skipBlanks()
+		if p.eof() {
+			break
+		}
+		switch p.peek() {
+		case '\n':
+			p.next()
+			continue
+		case '#':
+			p.skipLine()
+			continue
+		}
+
+		e, err := p.entry()
+		if err != nil {
+			return entries, err
+		}
+		p.vars[e.Key] = e.Value
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+func (p *parser) entry() (Entry, error) {
+	e := Entry{Line: p.line}
+
+	if strings.HasPrefix(p.src[p.pos:], "export") && p.pos+6 < len(p.src) &&
+		(p.src[p.pos+6] == ' ' || p.src[p.pos+6] == '\t') {
+		p.pos += 6
+		p.skipBlanks()
+	}
+
+	start := p.pos
+	for !p.eof() && isKeyChar(p.peek(), p.pos == start) {
+		p.pos++
+	}
+	e.Key = p.src[start:p.pos]
+	if e.Key == "" {
+		return e, p.errorf(e.Line, "expected a variable name, found %q", p.restOfLine())
+	}
+
+	p.skipBlanks()
+	if p.eof() || p.peek() != '=' {
+		return e, p.errorf(e.Line, "missing = after %s", e.Key)
+	}
+	p.pos++
+	p.skipBlanks()
+
+	var err error
+	switch {
+	case p.eof() || p.peek() == '\n':
+		e.Value = ""
+	case p.peek() == '\'':
+		e.Value, err = p.singleQuoted()
+	case p.peek() == '"':
+		e.Value, err = p.doubleQuoted()
+	default:
+		e.Value, err = p.unquoted()
+	}
+	if err != nil {
+		return e, err
+	}
+
+	// only a comment may follow the value
+	p.skipBlanks()
+	if !p.eof() && p.peek() == '#' {
+		p.skipLine()
+	} else if !p.eof() && p.peek() != '\n' {
+		return e, p.errorf(p.line, "unexpected %q after the value of %s", p.restOfLine(), e.Key)
+	}
+	return e, nil
+}
+
+func (p *parser) singleQuoted() (string, error) {
+	line := p.line
+	p.next()
+	start := p.pos
+	for !p.eof() {
+		if p.next() == '\'' {
+			return p.src[start : p.pos-1], nil
+		}
+	}
+	return "", p.errorf(line, "unterminated single-quoted value")
+}
+
+func (p *parser) doubleQuoted() (string, error) {
+	line := p.line
+	p.next()
+
+	var b strings.Builder
+	for !p.eof() {
+		c := p.next()
+		switch c {
+		case '"':
+			return b.String(), nil
+		case '\\':
+			if p.eof() {
+				break
+			}
+			b.WriteString(unescape(p.next()))
+		case '$':
+			v, err := p.variable()
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(v)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", p.errorf(line, "unterminated double-quoted value")
+}
+
+// unquoted reads to the end of the line or an inline comment, which must
+// be preceded by a blank.
+func (p *parser) unquoted() (string, error) {
+	var b strings.Builder
+	for !p.eof() && p.peek() != '\n' {
+		c := p.peek()
+		if c == '#' && p.pos > 0 && (p.src[p.pos-1] == ' ' || p.src[p.pos-1] == '\t') {
+			break
+		}
+		p.next()
+		switch {
+		case c == '\\' && !p.eof() && p.peek() == '$':
+			b.WriteByte(p.next())
+		case c == '$':
+			v, err := p.variable()
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(v)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return strings.TrimRight(b.String(), " \t"), nil
+}
+
+// variable expands the reference after a $: ${NAME}, ${NAME:-default} or
+// $NAME. A lone $ is kept.
+func (p *parser) variable() (string, error) {
+	if p.eof() {
+		return "$", nil
+	}
+
+	if p.peek() != '{' {
+		start := p.pos
+		for !p.eof() && isKeyChar(p.peek(), p.pos == start) {
+			p.pos++
+		}
+		if p.pos == start {
+			return "$", nil
+		}
+		return p.resolve(p.src[start:p.pos], "", false), nil
+	}
+
+	line := p.line
+	end := strings.IndexByte(p.src[p.pos:], '}')
+	if end < 0 || strings.Contains(p.src[p.pos:p.pos+end], "\n") {
+		return "", p.errorf(line, "unterminated ${ in value")
+	}
+	ref := p.src[p.pos+1 : p.pos+end]
+	p.pos += end + 1
+
+	key, def, hasDefault := strings.Cut(ref, ":-")
+	if !validKey(key) {
+		return "", p.errorf(line, "invalid variable reference ${%s}", ref)
+	}
+	return p.resolve(key, def, hasDefault), nil
+}
+
+func (p *parser) resolve(key, def string, hasDefault bool) string {
+	v, ok := p.vars[key]
+	if !ok && p.lookup != nil {
+		v, ok = p.lookup(key)
+	}
+	if (!ok || v == "") && hasDefault {
+		return def
+	}
+	return v
+}
+
+func (p *parser) restOfLine() string {
+	rest := p.src[p.pos:]
+	if i := strings.IndexByte(rest, '\n'); i >= 0 {
+		rest = rest[:i]
+	}
+	return rest
+}
+
+func unescape(c byte) string {
+	switch c {
+	case 'n':
+		return "\n"
+	case 'r':
+		return "\r"
+	case 't':
+		return "\t"
+	case '"', '\\', '$':
+		return string(c)
+	}
+	return "\\" + string(c)
+}
+
+func isKeyChar(c byte, first bool) bool {
+	switch {
+	case c == '_', c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z':
+		return true
+	case c >= '0' && c <= '9':
+		return !first
+	}
+	return false
+}
+
+func validKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if !isKeyChar(key[i], i == 0) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,140 @@
+package dotenv
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ezeqielle/pcli/internal/fsys"
+)
+
+func TestParse(t *testing.T) {
+	env := map[string]string{"HOME": "/home/me", "EMPTY": ""}
+	lookup := func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+
+	tests := []struct {
+		name string
+		src  string
+		want map[string]string
+	}{
+		{"plain", "A=1\nB=two", map[string]string{"A": "1", "B": "two"}},
+		{"empty value", "A=\nB=", map[string]string{"A": "", "B": ""}},
+		{"export prefix", "export A=1\nexport\tB=2", map[string]string{"A": "1", "B": "2"}},
+		{"key named export", "export=1", map[string]string{"export": "1"}},
+		{"key starting with export", "exported=1", map[string]string{"exported": "1"}},
+		{"spaces around =", "A = 1\nB\t=\t2  ", map[string]string{"A": "1", "B": "2"}},
+		{"CRLF", "A=1\r\nB=\"x\r\ny\"\r\n", map[string]string{"A": "1", "B": "x\ny"}},
+		{"comments and blank lines", "# head\n\n  # indented\nA=1\n", map[string]string{"A": "1"}},
+		{"inline comment", "A=1 # one\nB=2\t# two", map[string]string{"A": "1", "B": "2"}},
+		{"# inside a value", "A=a#b\nURL=http://x/#frag", map[string]string{"A": "a#b", "URL": "http://x/#frag"}},
+		{"# inside quotes", `A="a # b"` + "\nB='c # d' # comment", map[string]string{"A": "a # b", "B": "c # d"}},
+		{"single quotes are literal", `A='$HOME \n ${X}'`, map[string]string{"A": `$HOME \n ${X}`}},
+		{"double-quoted escapes", `A="a\nb\t\"c\"\\"`, map[string]string{"A": "a\nb\t\"c\"\\"}},
+		{"unknown escape kept", `A="\q"`, map[string]string{"A": `\q`}},
+		{"multi-line double quotes", "A=\"line 1\nline 2\"\nB=3", map[string]string{"A": "line 1\nline 2", "B": "3"}},
+		{"multi-line single quotes", "A='line 1\nline 2'", map[string]string{"A": "line 1\nline 2"}},
+		{"escaped $ in double quotes", `A="\$HOME"`, map[string]string{"A": "$HOME"}},
+		{"escaped $ unquoted", `A=\$HOME`, map[string]string{"A": "$HOME"}},
+		{"$VAR from lookup", "A=$HOME/x", map[string]string{"A": "/home/me/x"}},
+		{"$VAR stops at a dot", "A=$HOME.txt", map[string]string{"A": "/home/me.txt"}},
+		{"${VAR} from an earlier entry", "A=1\nB=${A}2", map[string]string{"A": "1", "B": "12"}},
+		{"earlier entry wins over lookup", "HOME=/srv\nA=${HOME}", map[string]string{"HOME": "/srv", "A": "/srv"}},
+		{"later entry is not visible", "A=${B}\nB=1", map[string]string{"A": "", "B": "1"}},
+		{"unknown variable is empty", "A=x${NOPE}y", map[string]string{"A": "xy"}},
+		{"default when unset", "A=${NOPE:-fallback}", map[string]string{"A": "fallback"}},
+		{"default when empty", "A=${EMPTY:-fallback}", map[string]string{"A": "fallback"}},
+		{"default unused when set", "B=1\nA=${B:-fallback}", map[string]string{"B": "1", "A": "1"}},
+		{"default unused from lookup", "A=${HOME:-fallback}", map[string]string{"A": "/home/me"}},
+		{"interpolation in double quotes", `A="${HOME} and $HOME"`, map[string]string{"A": "/home/me and /home/me"}},
+		{"$ at end of input", "A=cost$", map[string]string{"A": "cost$"}},
+		{"$ at end of line", "A=cost$\nB=1", map[string]string{"A": "cost$", "B": "1"}},
+		{"$ before a non-name", "A=$1 $-", map[string]string{"A": "$1 $-"}},
+		{"$ at end of double quotes", `A="cost$"`, map[string]string{"A": "cost$"}},
+		{"redefinition wins", "A=1\nA=2", map[string]string{"A": "2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := Parse([]byte(tt.src), ".env", lookup)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.src, err)
+			}
+			if got := Map(entries); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %q, want %q", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLines(t *testing.T) {
+	src := "# comment\nA=1\n\nB=\"multi\nline\"\nC=3\n"
+	entries, err := Parse([]byte(src), ".env", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Entry{
+		{Key: "A", Value: "1", Line: 2},
+		{Key: "B", Value: "multi\nline", Line: 4},
+		{Key: "C", Value: "3", Line: 6},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("Parse = %+v, want %+v", entries, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"missing =", "A=1\nBROKEN\n", ".env:2: missing = after BROKEN"},
+		{"no name", "=1", `.env:1: expected a variable name, found "=1"`},
+		{"bad name", "1A=1", `.env:1: expected a variable name, found "1A=1"`},
+		{"unterminated double quotes", "A=1\nB=\"open\nC=3\n", ".env:2: unterminated double-quoted value"},
+		{"unterminated single quotes", "A='open", ".env:1: unterminated single-quoted value"},
+		{"text after quotes", `A="x" y`, `.env:1: unexpected "y" after the value of A`},
+		{"unterminated ${", "A=${B\nC=1", ".env:1: unterminated ${ in value"},
+		{"invalid reference", "A=${1B}", ".env:1: invalid variable reference ${1B}"},
+		{"CRLF line numbers", "A=1\r\nB\r\n", ".env:2: missing = after B"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.src), ".env", nil)
+			if err == nil {
+				t.Fatalf("Parse(%q) succeeded, want %q", tt.src, tt.want)
+			}
+			var syntax *SyntaxError
+			if !errors.As(err, &syntax) {
+				t.Errorf("Parse(%q) error %T, want *SyntaxError", tt.src, err)
+			}
+			if err.Error() != tt.want {
+				t.Errorf("Parse(%q) error = %q, want %q", tt.src, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	fs := fsys.NewMemory()
+
+	got, err := ReadFile(fs, "/p/.env")
+	if err != nil || len(got) != 0 {
+		t.Errorf("ReadFile(missing) = %v, %v; want an empty map", got, err)
+	}
+
+	if err := fs.MkdirAll("/p", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.WriteFile("/p/.env", []byte("A=1\nB\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadFile(fs, "/p/.env"); err == nil || err.Error() != "/p/.env:2: missing = after B" {
+		t.Errorf("ReadFile(malformed) error = %v", err)
+	}
+}
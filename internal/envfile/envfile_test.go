@@ -0,0 +1,33 @@
+package envfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKeys(t *testing.T) {
+	got, err := Keys([]byte("# db\nexport DB_HOST=localhost\nDB_PASSWORD=\"a # b\"\nEMPTY=\n"), ".env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bool{"DB_HOST": true, "DB_PASSWORD": true, "EMPTY": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Keys = %v, want %v", got, want)
+	}
+}
+
+func TestKeysMalformed(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"A=1\nBROKEN\n", ".env.example:2: missing = after BROKEN"},
+		{"A=\"open\n", ".env.example:1: unterminated double-quoted value"},
+	}
+
+	for _, tt := range tests {
+		if _, err := Keys([]byte(tt.src), ".env.example"); err == nil || err.Error() != tt.want {
+			t.Errorf("Keys(%q) error = %v, want %q", tt.src, err, tt.want)
+		}
+	}
+}
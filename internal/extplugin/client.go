@@ -0,0 +1,142 @@
+package extplugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/ezeqielle/pcli/internal/question"
+)
+
+// callTimeout bounds a single plugin call.
+const callTimeout = 30 * time.Second
+
+// transport performs one request/response exchange with a plugin:
+// it feeds req on stdin and returns what the plugin wrote on stdout.
+type transport interface {
+	roundTrip(ctx context.Context, req []byte) ([]byte, error)
+}
+
+// Client calls a plugin, one process (or module instance) per call.
+type Client struct {
+	Path      string
+	transport transport
+}
+
+// NewExecClient returns a client for a native executable plugin.
+func NewExecClient(path string) *Client {
+	return &Client{Path: path, transport: execTransport{path: path}}
+}
+
+// Sandboxed reports whether the plugin runs in the WASM sandbox, whose plans
+// may not run commands on the host.
+func (c *Client) Sandboxed() bool {
+	_, ok := c.transport.(*wasmTransport)
+	return ok
+}
+
+// Call sends method with params and decodes the result into result.
+func (c *Client) Call(method string, params, result any) error {
+	req, err := json.Marshal(request{JSONRPC: "2.0", ID: 1, Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout)
+	defer cancel()
+
+	out, err := c.transport.roundTrip(ctx, append(req, '\n'))
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", c.Path, method, err)
+	}
+
+	var resp response
+	if err := json.NewDecoder(bytes.NewReader(out)).Decode(&resp); err != nil {
+		return fmt.Errorf("%s %s: invalid response: %w", c.Path, method, err)
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("%s %s: %w", c.Path, method, resp.Error)
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		return fmt.Errorf("%s %s: invalid result: %w", c.Path, method, err)
+	}
+	return nil
+}
+
+type execTransport struct {
+	path string
+}
+
+func (t execTransport) roundTrip(ctx context.Context, req []byte) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, t.path)
+	cmd.Stdin = bytes.NewReader(req)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+// Describe asks the plugin who it is and checks the protocol version.
+func (c *Client) Describe() (Description, error) {
+	var d Description
+	if err := c.Call("describe", describeParams{ProtocolVersion: ProtocolVersion}, &d); err != nil {
+		return d, err
+	}
+	if d.ProtocolVersion != ProtocolVersion {
+		return d, fmt.Errorf("%s speaks protocol version %d, pcli speaks %d", c.Path, d.ProtocolVersion, ProtocolVersion)
+	}
+	if d.ID == "" {
+		return d, fmt.Errorf("%s: describe returned no id", c.Path)
+	}
+	if d.Kind == "" {
+		d.Kind = KindPostCreate
+	}
+	if d.Kind != KindPostCreate && d.Kind != KindProjectType {
+		return d, fmt.Errorf("%s: unknown plugin kind %q", c.Path, d.Kind)
+	}
+	if d.Name == "" {
+		d.Name = d.ID
+	}
+	return d, nil
+}
+
+func (c *Client) Questions(ctx Context) ([]question.Question, error) {
+	var res questionsResult
+	if err := c.Call("questions", ctx, &res); err != nil {
+		return nil, err
+	}
+	for _, q := range res.Questions {
+		if err := q.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", c.Path, err)
+		}
+	}
+	return res.Questions, nil
+}
+
+func (c *Client) Validate(ctx Context, answers question.Answers) (map[string]string, error) {
+	var res validateResult
+	if err := c.Call("validate", answersParams{Context: ctx, Answers: answers}, &res); err != nil {
+		return nil, err
+	}
+	return res.Errors, nil
+}
+
+func (c *Client) Apply(ctx Context, answers question.Answers) (ApplyResult, error) {
+	var res ApplyResult
+	err := c.Call("apply", answersParams{Context: ctx, Answers: answers}, &res)
+	return res, err
+}
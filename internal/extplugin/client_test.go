@@ -0,0 +1,201 @@
+package extplugin
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ezeqielle/pcli/internal/fileplan"
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/question"
+	"github.com/ezeqielle/pcli/internal/runner"
+)
+
+// pluginEnv makes the test binary act as an external plugin; its value
+// picks how the plugin behaves.
+const pluginEnv = "PCLI_TEST_PLUGIN"
+
+func TestMain(m *testing.M) {
+	if mode := os.Getenv(pluginEnv); mode != "" {
+		servePlugin(mode)
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// servePlugin answers one request on stdin like a pcli-plugin-* executable.
+func servePlugin(mode string) {
+	line, _ := bufio.NewReader(os.Stdin).ReadBytes('\n')
+	var req struct {
+		ID     int             `json:"id"`
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}
+	if err := json.Unmarshal(line, &req); err != nil {
+		fmt.Fprintln(os.Stderr, "bad request:", err)
+		os.Exit(2)
+	}
+
+	var result any
+	switch {
+	case mode == "garbage":
+		fmt.Println("not json")
+		return
+	case mode == "fail":
+		fmt.Fprintln(os.Stderr, "boom")
+		os.Exit(1)
+	case mode == "error":
+		fmt.Printf(`{"jsonrpc":"2.0","id":%d,"error":{"code":-32601,"message":"no such method"}}`+"\n", req.ID)
+		return
+
+	case req.Method == "describe":
+		version := ProtocolVersion
+		if mode == "old" {
+			version = ProtocolVersion + 1
+		}
+		result = map[string]any{"protocol_version": version, "id": "hello", "tags": []string{"demo"}}
+
+	case req.Method == "questions":
+		result = map[string]any{"questions": []map[string]any{
+			{"id": "name", "type": "text", "label": "Name", "required": true},
+		}}
+
+	case req.Method == "validate", req.Method == "apply":
+		var p answersParams
+		if err := json.Unmarshal(req.Params, &p); err != nil {
+			fmt.Fprintln(os.Stderr, "bad params:", err)
+			os.Exit(2)
+		}
+		name := p.Answers.String("name")
+		if req.Method == "validate" {
+			if name == "world" {
+				result = validateResult{}
+			} else {
+				result = validateResult{Errors: map[string]string{"name": "must be world"}}
+			}
+			break
+		}
+		result = ApplyResult{Plan: fileplan.Plan{
+			Files:    []fileplan.File{{Path: "hello.txt", Content: "hello " + name + " from " + p.ProjectType + "\n"}},
+			Messages: []string{"said hello"},
+		}}
+	}
+
+	data, _ := json.Marshal(result)
+	fmt.Printf(`{"jsonrpc":"2.0","id":%d,"result":%s}`+"\n", req.ID, data)
+}
+
+// testClient returns a client running the test binary as a plugin in mode.
+func testClient(t *testing.T, mode string) *Client {
+	t.Helper()
+
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(pluginEnv, mode)
+	return NewExecClient(exe)
+}
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		mode string
+		err  string
+	}{
+		{"ok", ""},
+		{"old", "speaks protocol version 2"},
+		{"error", "plugin error -32601: no such method"},
+		{"garbage", "invalid response"},
+		{"fail", "boom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			d, err := testClient(t, tt.mode).Describe()
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("Describe error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d.ID != "hello" || d.Name != "hello" || d.Kind != KindPostCreate || !reflect.DeepEqual(d.Tags, []string{"demo"}) {
+				t.Errorf("Describe = %+v", d)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	c := testClient(t, "ok")
+	ctx := Context{ProjectPath: "/p", ProjectType: "go"}
+
+	qs, err := c.Questions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qs) != 1 || qs[0].ID != "name" || qs[0].Type != question.Text || !qs[0].Required {
+		t.Errorf("Questions = %+v", qs)
+	}
+
+	errs, err := c.Validate(ctx, question.Answers{"name": "moon"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"name": "must be world"}; !reflect.DeepEqual(errs, want) {
+		t.Errorf("Validate = %v, want %v", errs, want)
+	}
+
+	res, err := c.Apply(ctx, question.Answers{"name": "world"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Files) != 1 || res.Files[0].Content != "hello world from go\n" {
+		t.Errorf("Apply = %+v", res)
+	}
+}
+
+func TestLoadAndApply(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(pluginEnv, "ok")
+
+	fs := fsys.NewMemory()
+	if err := fs.MkdirAll("/p", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(exe, runner.NewFake(), fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, ok := loaded.(*PostCreatePlugin)
+	if !ok {
+		t.Fatalf("Load = %T, want *PostCreatePlugin", loaded)
+	}
+
+	if _, err := p.Apply("/p", "go", question.Answers{"name": "moon"}); err == nil || !strings.Contains(err.Error(), "name: must be world") {
+		t.Errorf("Apply with invalid answers: %v", err)
+	}
+	if fsys.Exists(fs, "/p/hello.txt") {
+		t.Error("a rejected answer still wrote the plan")
+	}
+
+	summary, err := p.Apply("/p", "go", question.Answers{"name": "world"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := fs.ReadFile("/p/hello.txt"); string(data) != "hello world from go\n" {
+		t.Errorf("hello.txt = %q", data)
+	}
+	if !strings.Contains(strings.Join(summary, "\n"), "said hello") {
+		t.Errorf("summary = %q, want the plugin's message", summary)
+	}
+}
@@ -0,0 +1,80 @@
+package extplugin
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Prefix is the executable name prefix of external plugins.
+const Prefix = "pcli-plugin-"
+
+// PluginDirs returns the directories searched before PATH:
+// $PCLI_PLUGIN_PATH (list separated like PATH), then
+// $XDG_DATA_HOME/pcli/plugins (default ~/.local/share/pcli/plugins).
+func PluginDirs() []string {
+	var dirs []string
+	if env := os.Getenv("PCLI_PLUGIN_PATH"); env != "" {
+		dirs = append(dirs, filepath.SplitList(env)...)
+	}
+
+	if data := os.Getenv("XDG_DATA_HOME"); data != "" && filepath.IsAbs(data) {
+		dirs = append(dirs, filepath.Join(data, "pcli", "plugins"))
+	} else if home, err := os.UserHomeDir(); err == nil && home != "" {
+		dirs = append(dirs, filepath.Join(home, ".local", "share", "pcli", "plugins"))
+	}
+	return dirs
+}
+
+// Discover returns the paths of every pcli-plugin-* executable in the plugin
+// directories and PATH, and of every *.wasm module in the plugin directories.
+// When the same name appears twice, the first wins.
+func Discover() []string {
+	pluginDirs := PluginDirs()
+	dirs := append(append([]string(nil), pluginDirs...), filepath.SplitList(os.Getenv("PATH"))...)
+
+	seen := map[string]bool{}
+	var out []string
+	for i, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		inPluginDir := i < len(pluginDirs)
+
+		var names []string
+		for _, e := range entries {
+			if seen[e.Name()] {
+				continue
+			}
+			if strings.HasPrefix(e.Name(), Prefix) || (inPluginDir && IsWasm(e.Name())) {
+				names = append(names, e.Name())
+			}
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			path := filepath.Join(dir, name)
+			info, err := os.Stat(path)
+			if err != nil || info.IsDir() {
+				continue
+			}
+			// WASM modules run in pcli's sandbox and need no exec bit
+			if !IsWasm(name) && info.Mode().Perm()&0o111 == 0 {
+				continue
+			}
+			seen[name] = true
+			out = append(out, path)
+		}
+	}
+	return out
+}
+
+// IsWasm reports whether path names a WebAssembly plugin module.
+func IsWasm(path string) bool {
+	return strings.HasSuffix(path, ".wasm")
+}
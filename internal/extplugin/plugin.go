@@ -0,0 +1,217 @@
+package extplugin
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ezeqielle/pcli/internal/fileplan"
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/journal"
+	"github.com/ezeqielle/pcli/internal/pluginmeta"
+	"github.com/ezeqielle/pcli/internal/postplugin"
+	"github.com/ezeqielle/pcli/internal/projecttype"
+	"github.com/ezeqielle/pcli/internal/question"
+	"github.com/ezeqielle/pcli/internal/runner"
+)
+
+// Plugin adapts an external executable to projecttype.Plugin or
+// postplugin.Plugin, depending on the kind it describes itself as.
+type Plugin struct {
+	client *Client
+	desc   Description
+
+	runner runner.Runner
+	fs     fsys.FS
+}
+
+func (p *Plugin) ID() string {
+	return p.desc.ID
+}
+
+func (p *Plugin) DisplayName() string {
+	return p.desc.Name
+}
+
+func (p *Plugin) Description() string {
+	return p.desc.Description
+}
+
+func (p *Plugin) Metadata() pluginmeta.Metadata {
+	return p.desc.Metadata
+}
+
+// Path returns the plugin executable.
+func (p *Plugin) Path() string {
+	return p.client.Path
+}
+
+// ProjectTypePlugin is an external project type plugin.
+type ProjectTypePlugin struct {
+	*Plugin
+}
+
+func (p *ProjectTypePlugin) NewWizard() tea.Model {
+	return newModel(p.Plugin, Context{ProjectType: p.desc.ID})
+}
+
+// PostCreatePlugin is an external post-create plugin.
+type PostCreatePlugin struct {
+	*Plugin
+}
+
+func (p *PostCreatePlugin) NewWizard(projectPath, projectType string) tea.Model {
+	m := newModel(p.Plugin, Context{ProjectPath: projectPath, ProjectType: projectType})
+	if !p.desc.Supports(projectType) {
+		m.summary = []string{fmt.Sprintf("%s does not support %s projects (skipped).", p.desc.Name, projectType)}
+		m.step = stepDone
+	}
+	return m
+}
+
+// Questions asks the plugin for its questions.
+func (p *ProjectTypePlugin) Questions() ([]question.Question, error) {
+	return p.client.Questions(Context{})
+}
+
+// Create validates the answers with the plugin and applies its plan.
+func (p *ProjectTypePlugin) Create(answers question.Answers) (string, error) {
+	dir, _, err := p.run(Context{}, answers)
+	return dir, err
+}
+
+// Questions asks the plugin for its questions.
+func (p *PostCreatePlugin) Questions(projectPath, projectType string) ([]question.Question, error) {
+	return p.client.Questions(Context{ProjectPath: projectPath, ProjectType: projectType})
+}
+
+// Apply validates the answers with the plugin and applies its plan.
+func (p *PostCreatePlugin) Apply(projectPath, projectType string, answers question.Answers) ([]string, error) {
+	if !p.desc.Supports(projectType) {
+		return []string{fmt.Sprintf("%s does not support %s projects (skipped).", p.desc.Name, projectType)}, nil
+	}
+	_, summary, err := p.run(Context{ProjectPath: projectPath, ProjectType: projectType}, answers)
+	return summary, err
+}
+
+// run validates answers with the plugin, then applies them.
+func (p *Plugin) run(ctx Context, answers question.Answers) (string, []string, error) {
+	errs, err := p.client.Validate(ctx, answers)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(errs) > 0 {
+		ids := make([]string, 0, len(errs))
+		for id := range errs {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+
+		var invalid []error
+		for _, id := range ids {
+			invalid = append(invalid, fmt.Errorf("%s: %s", id, errs[id]))
+		}
+		return "", nil, errors.Join(invalid...)
+	}
+
+	return p.applyAnswers(ctx, answers)
+}
+
+// applyAnswers asks the plugin for its plan and applies it inside the
+// project directory, returning that directory and what was done.
+func (p *Plugin) applyAnswers(ctx Context, answers question.Answers) (string, []string, error) {
+	res, err := p.client.Apply(ctx, answers)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if p.client.Sandboxed() && len(res.Commands) > 0 {
+		return "", nil, fmt.Errorf("%s: WASM plugins cannot run commands", p.desc.ID)
+	}
+
+	root := ctx.ProjectPath
+	if p.desc.Kind == KindProjectType {
+		root = res.ProjectDir
+		if root == "" {
+			return "", nil, fmt.Errorf("%s: apply returned no project_dir", p.desc.ID)
+		}
+		if p.client.Sandboxed() {
+			// a sandboxed plugin only creates projects below the directory
+			// pcli was started in
+			base, err := os.Getwd()
+			if err != nil {
+				return "", nil, err
+			}
+			if root, err = fileplan.Within(base, root); err != nil {
+				return "", nil, fmt.Errorf("%s: project_dir: %w", p.desc.ID, err)
+			}
+		}
+	}
+
+	summary, err := fileplan.Apply(p.fs, p.runner, root, res.Plan)
+	if j := journal.Of(p.fs); j != nil && err == nil {
+		if p.desc.Kind == KindProjectType {
+			j.SetProject(p.desc.ID, "", root)
+		} else {
+			j.AddPlugin(p.desc.ID)
+		}
+	}
+	return root, summary, err
+}
+
+// Load describes the plugin at path (an executable or a .wasm module)
+// and returns its adapter:
+// a *ProjectTypePlugin or a *PostCreatePlugin.
+func Load(path string, r runner.Runner, fs fsys.FS) (any, error) {
+	var client *Client
+	if IsWasm(path) {
+		c, err := NewWasmClient(path)
+		if err != nil {
+			return nil, err
+		}
+		client = c
+	} else {
+		client = NewExecClient(path)
+	}
+
+	desc, err := client.Describe()
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Plugin{client: client, desc: desc, runner: r, fs: fs}
+	if desc.Kind == KindProjectType {
+		return &ProjectTypePlugin{Plugin: p}, nil
+	}
+	return &PostCreatePlugin{Plugin: p}, nil
+}
+
+// RegisterDiscovered loads every discovered executable plugin into the
+// project type and post-create registries. Plugins that fail to load are
+// skipped and reported in the returned error.
+func RegisterDiscovered(r runner.Runner, fs fsys.FS) error {
+	var errs []error
+	for _, path := range Discover() {
+		loaded, err := Load(path, r, fs)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		switch p := loaded.(type) {
+		case *ProjectTypePlugin:
+			err = projecttype.Register(p)
+		case *PostCreatePlugin:
+			err = postplugin.Register(p)
+		default:
+			err = fmt.Errorf("unsupported plugin")
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
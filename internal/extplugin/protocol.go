@@ -0,0 +1,100 @@
+package extplugin
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ezeqielle/pcli/internal/fileplan"
+	"github.com/ezeqielle/pcli/internal/pluginmeta"
+	"github.com/ezeqielle/pcli/internal/question"
+)
+
+// ProtocolVersion is the version of the stdio protocol spoken by pcli.
+// A plugin must answer describe with the same version to be loaded.
+//
+// Each call starts the plugin executable, writes one JSON-RPC 2.0 request
+// line to its stdin and reads one JSON-RPC 2.0 response from its stdout.
+// Methods:
+//
+//	describe  {protocol_version}                          -> Description (with metadata)
+//	questions {project_path, project_type}                -> {questions: [question.Question]}
+//	validate  {project_path, project_type, answers}       -> {errors: {question_id: message}}
+//	apply     {project_path, project_type, answers}       -> {project_dir?, dirs, files, commands, messages}
+//
+// apply only returns a plan: pcli writes the files itself, inside the project
+// directory, through its journaled (and possibly dry-run) filesystem.
+const ProtocolVersion = 1
+
+// Kinds of external plugins.
+const (
+	KindPostCreate  = "postcreate"
+	KindProjectType = "projecttype"
+)
+
+type request struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      int    `json:"id"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      int             `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("plugin error %d: %s", e.Code, e.Message)
+}
+
+// Description is a plugin's answer to describe.
+type Description struct {
+	ProtocolVersion int    `json:"protocol_version"`
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Description     string `json:"description,omitempty"`
+	// Kind is KindPostCreate (default) or KindProjectType.
+	Kind string `json:"kind,omitempty"`
+
+	// Metadata: version, author, tags, project_types, required_tools,
+	// after, before and conflicts.
+	pluginmeta.Metadata
+}
+
+// Context tells the plugin which project it is working on. ProjectPath is
+// empty for project type plugins, which choose the directory in apply.
+type Context struct {
+	ProjectPath string `json:"project_path"`
+	ProjectType string `json:"project_type"`
+}
+
+type describeParams struct {
+	ProtocolVersion int `json:"protocol_version"`
+}
+
+type questionsResult struct {
+	Questions []question.Question `json:"questions"`
+}
+
+type answersParams struct {
+	Context
+	Answers question.Answers `json:"answers"`
+}
+
+type validateResult struct {
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// ApplyResult is the plan returned by apply. Project type plugins set
+// ProjectDir to the absolute directory the plan is relative to.
+type ApplyResult struct {
+	ProjectDir string `json:"project_dir,omitempty"`
+	fileplan.Plan
+}
@@ -0,0 +1,145 @@
+package extplugin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"github.com/tetratelabs/wazero/sys"
+)
+
+// wasmMemoryLimitPages caps a plugin instance at 64 MiB (64 KiB pages).
+const wasmMemoryLimitPages = 1024
+
+// wasmOutputLimit caps what a plugin instance may write to stdout, and
+// wasmStderrLimit what is kept of its stderr.
+const (
+	wasmOutputLimit = 16 << 20
+	wasmStderrLimit = 64 << 10
+)
+
+var (
+	wasmOnce    sync.Once
+	wasmRuntime wazero.Runtime
+	wasmErr     error
+)
+
+// runtime returns the process-wide WASM runtime with WASI preview 1.
+func runtime() (wazero.Runtime, error) {
+	wasmOnce.Do(func() {
+		ctx := context.Background()
+		cfg := wazero.NewRuntimeConfig().
+			WithMemoryLimitPages(wasmMemoryLimitPages).
+			WithCloseOnContextDone(true)
+
+		if dir, err := wasmCacheDir(); err == nil {
+			if cache, err := wazero.NewCompilationCacheWithDir(dir); err == nil {
+				cfg = cfg.WithCompilationCache(cache)
+			}
+		}
+
+		wasmRuntime = wazero.NewRuntimeWithConfig(ctx, cfg)
+		if _, err := wasi_snapshot_preview1.Instantiate(ctx, wasmRuntime); err != nil {
+			wasmErr = fmt.Errorf("failed to initialise WASI: %w", err)
+		}
+	})
+	return wasmRuntime, wasmErr
+}
+
+// wasmCacheDir returns $XDG_CACHE_HOME/pcli/wasm (default ~/.cache/pcli/wasm),
+// where compiled modules are cached between runs.
+func wasmCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "pcli", "wasm"), nil
+}
+
+// wasmTransport runs a WASI command module per call.
+//
+// The module gets the request on stdin and nothing else: no preopened
+// directories, no environment, no clock beyond WASI defaults and no network.
+// It can only answer with a plan, which pcli applies inside the project.
+type wasmTransport struct {
+	path   string
+	module wazero.CompiledModule
+}
+
+// NewWasmClient compiles the module at path and returns a client for it.
+func NewWasmClient(path string) (*Client, error) {
+	rt, err := runtime()
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	module, err := rt.CompileModule(context.Background(), code)
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid WASM module: %w", path, err)
+	}
+
+	return &Client{Path: path, transport: &wasmTransport{path: path, module: module}}, nil
+}
+
+func (t *wasmTransport) roundTrip(ctx context.Context, req []byte) ([]byte, error) {
+	rt, err := runtime()
+	if err != nil {
+		return nil, err
+	}
+
+	stdout := &limitedBuffer{limit: wasmOutputLimit}
+	stderr := &limitedBuffer{limit: wasmStderrLimit}
+	cfg := wazero.NewModuleConfig().
+		WithName("").
+		WithArgs(filepath.Base(t.path)).
+		WithStdin(bytes.NewReader(req)).
+		WithStdout(stdout).
+		WithStderr(stderr)
+
+	mod, err := rt.InstantiateModule(ctx, t.module, cfg)
+	if mod != nil {
+		_ = mod.Close(ctx)
+	}
+
+	var exitErr *sys.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 0 {
+		err = nil
+	}
+	if err == nil && stdout.overflow {
+		err = fmt.Errorf("output exceeds %d bytes", wasmOutputLimit)
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+// limitedBuffer is a bytes.Buffer that refuses writes beyond limit bytes.
+type limitedBuffer struct {
+	bytes.Buffer
+	limit    int
+	overflow bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > b.limit {
+		b.overflow = true
+		return 0, errors.New("output limit exceeded")
+	}
+	return b.Buffer.Write(p)
+}
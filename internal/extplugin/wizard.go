@@ -0,0 +1,276 @@
+package extplugin
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/nav"
+	"github.com/ezeqielle/pcli/internal/postplugin"
+	"github.com/ezeqielle/pcli/internal/question"
+	"github.com/ezeqielle/pcli/internal/ui"
+	"github.com/ezeqielle/pcli/internal/wizard"
+)
+
+// -------------------------------------------
+// EXTERNAL PLUGIN WIZARD MODEL
+// -------------------------------------------
+
+type step int
+
+const (
+	stepLoading step = iota
+	stepAsking
+	stepWorking
+	stepRollback
+	stepDone
+)
+
+type questionsMsg struct {
+	Questions []question.Question
+	Err       error
+}
+
+type validatedMsg struct {
+	Errors map[string]string
+	Err    error
+}
+
+type appliedMsg struct {
+	Dir     string
+	Summary []string
+	Err     error
+}
+
+// Model asks an external plugin's questions with the generic wizard form,
+// then validates and applies the answers.
+type Model struct {
+	step step
+
+	plugin *Plugin
+	ctx    Context
+
+	form    wizard.Form
+	answers question.Answers
+
+	projectDir string
+	summary    []string
+	errMsg     string
+
+	rollback wizard.Rollback
+}
+
+func newModel(p *Plugin, ctx Context) Model {
+	return Model{
+		step:       stepLoading,
+		plugin:     p,
+		ctx:        ctx,
+		answers:    question.Answers{},
+		projectDir: ctx.ProjectPath,
+	}
+}
+
+func (m Model) Init() tea.Cmd {
+	if m.step != stepLoading {
+		return nil
+	}
+
+	client, ctx := m.plugin.client, m.ctx
+	return func() tea.Msg {
+		qs, err := client.Questions(ctx)
+		return questionsMsg{Questions: qs, Err: err}
+	}
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case questionsMsg:
+		if msg.Err != nil {
+			m.errMsg = msg.Err.Error()
+			m.step = stepDone
+			return m, nil
+		}
+		m.form = wizard.NewForm("", msg.Questions, nil)
+		if m.form.State() == wizard.Submitted {
+			m.step = stepWorking
+			return m, m.validate()
+		}
+		m.step = stepAsking
+		return m, m.form.Init()
+
+	case validatedMsg:
+		if msg.Err != nil {
+			m.errMsg = msg.Err.Error()
+			m.step = stepDone
+			return m, nil
+		}
+		if len(msg.Errors) > 0 {
+			var cmd tea.Cmd
+			m.form, cmd = m.form.WithErrors(msg.Errors)
+			if m.form.State() != wizard.Asking {
+				// errors for unknown questions: nothing to re-ask
+				m.errMsg = fmt.Sprint(msg.Errors)
+				m.step = stepDone
+				return m, nil
+			}
+			m.step = stepAsking
+			return m, cmd
+		}
+		return m, m.apply()
+
+	case appliedMsg:
+		m.summary = msg.Summary
+		if msg.Err != nil {
+			m.errMsg = msg.Err.Error()
+			m.step = stepDone
+			if wizard.CanRollback(m.plugin.fs) {
+				m.rollback = wizard.NewRollback(m.plugin.fs, m.plugin.desc.Name, "Applying failed: "+m.errMsg)
+				m.step = stepRollback
+			}
+			return m, nil
+		}
+		m.projectDir = msg.Dir
+
+		m.step = stepDone
+		return m, m.postCreate()
+
+	case tea.KeyMsg:
+		if m.step == stepRollback {
+			return m.updateRollback(msg)
+		}
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+
+		switch m.step {
+		case stepDone:
+			// any key finishes this plugin; after a rollback no further
+			// post-create plugin runs
+			if m.plugin.desc.Kind == KindPostCreate {
+				if m.rollback.State() == wizard.RolledBack {
+					return m, postplugin.Abort
+				}
+				return m, postplugin.Finish
+			}
+			return m, tea.Quit
+		}
+	}
+
+	if m.step == stepAsking {
+		var cmd tea.Cmd
+		m.form, cmd = m.form.Update(msg)
+
+		switch m.form.State() {
+		case wizard.Submitted:
+			m.answers = m.form.Answers()
+			m.step = stepWorking
+			return m, m.validate()
+
+		case wizard.Cancelled:
+			if m.plugin.desc.Kind == KindPostCreate {
+				m.summary = []string{"Skipped " + m.plugin.desc.Name + "."}
+				m.step = stepDone
+				return m, nil
+			}
+			m.form, cmd = m.form.Reopen()
+		}
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+func (m Model) updateRollback(key tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.rollback, cmd = m.rollback.Update(key)
+
+	switch m.rollback.State() {
+	case wizard.RolledBack:
+		m.summary = m.rollback.Summary()
+		m.errMsg = ""
+		if err := m.rollback.Err(); err != nil {
+			m.errMsg = "rollback incomplete: " + err.Error()
+		}
+		m.step = stepDone
+
+	case wizard.Kept:
+		m.step = stepDone
+	}
+	return m, cmd
+}
+
+// postCreate hands a created project off to its post-create plugins, like
+// built-in project types. It returns nil for post-create plugins and when
+// nothing runs next.
+func (m Model) postCreate() tea.Cmd {
+	if m.plugin.desc.Kind != KindProjectType || m.errMsg != "" {
+		return nil
+	}
+	chain := postplugin.NewChainFor(m.projectDir, m.plugin.desc.ID)
+	if chain.Empty() {
+		return nil
+	}
+	return nav.Push("post-create:"+m.projectDir, "Post-create", chain)
+}
+
+func (m Model) validate() tea.Cmd {
+	client, ctx, answers := m.plugin.client, m.ctx, m.answers
+	return func() tea.Msg {
+		errs, err := client.Validate(ctx, answers)
+		return validatedMsg{Errors: errs, Err: err}
+	}
+}
+
+func (m Model) apply() tea.Cmd {
+	p, ctx, answers := m.plugin, m.ctx, m.answers
+	return func() tea.Msg {
+		dir, summary, err := p.applyAnswers(ctx, answers)
+		return appliedMsg{Dir: dir, Summary: summary, Err: err}
+	}
+}
+
+func (m Model) View() string {
+	if m.step == stepRollback {
+		return m.rollback.View()
+	}
+
+	var b strings.Builder
+
+	b.WriteString(m.plugin.desc.Name + "\n\n")
+	if m.projectDir != "" {
+		b.WriteString("Project: " + m.projectDir + "\n\n")
+	}
+
+	switch m.step {
+	case stepLoading:
+		b.WriteString("Loading questions...\n")
+
+	case stepWorking:
+		b.WriteString("Applying...\n")
+
+	case stepAsking:
+		b.WriteString(m.form.View())
+
+	case stepDone:
+		if len(m.summary) == 0 && m.errMsg == "" {
+			b.WriteString("No changes were applied.\n")
+		}
+		for _, line := range m.summary {
+			b.WriteString("- " + line + "\n")
+		}
+
+		if p, dryRun := fsys.PlanOf(m.plugin.fs); dryRun {
+			b.WriteString("\nDry run – nothing was written. Plan:\n\n")
+			b.WriteString(p.Render())
+		}
+
+		if m.errMsg != "" {
+			b.WriteString(ui.ErrorLine(m.errMsg))
+		}
+		b.WriteString(ui.Help("[any key] Exit"))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,225 @@
+package fileplan
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ezeqielle/pcli/internal/conflict"
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/runner"
+)
+
+// File is a file a plugin wants written, relative to the project directory.
+type File struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+	// Mode is the octal permission, e.g. 0o644. Zero means 0o644; special
+	// bits (setuid, setgid, sticky) and world write are dropped.
+	Mode fs.FileMode `json:"mode,omitempty"`
+	// OnConflict names the conflict.Policy used when the file exists. Empty means skip.
+	OnConflict string `json:"on_conflict,omitempty"`
+}
+
+// Command is a command a plugin wants run, with Dir relative to the project directory.
+type Command struct {
+	Name string   `json:"name"`
+	Args []string `json:"args,omitempty"`
+	Dir  string   `json:"dir,omitempty"`
+}
+
+// Plan is what an out-of-process plugin returns from apply.
+// pcli performs the writes itself, so they are journaled, honour dry-run and
+// can never leave the project directory.
+type Plan struct {
+	Dirs     []string  `json:"dirs,omitempty"`
+	Files    []File    `json:"files,omitempty"`
+	Commands []Command `json:"commands,omitempty"`
+	Messages []string  `json:"messages,omitempty"`
+}
+
+// Resolve joins rel onto root, refusing absolute paths and paths escaping root.
+// It only looks at the path; Contained checks it against the disk.
+func Resolve(root, rel string) (string, error) {
+	if rel == "" || filepath.IsAbs(rel) {
+		return "", fmt.Errorf("invalid path %q: must be relative to the project", rel)
+	}
+	full := filepath.Join(root, rel)
+	if !inside(root, full) {
+		return "", fmt.Errorf("invalid path %q: escapes the project directory", rel)
+	}
+	return full, nil
+}
+
+func inside(root, path string) bool {
+	r, err := filepath.Rel(root, path)
+	return err == nil && r != ".." && !strings.HasPrefix(r, ".."+string(filepath.Separator))
+}
+
+// Contained checks that full, a path inside root, stays inside it on disk:
+// symlinks in the part of both paths that exists are followed, and full
+// must not be a symlink itself, so a link in the project cannot send a
+// write elsewhere.
+func Contained(root, full string) error {
+	if info, err := os.Lstat(full); err == nil && info.Mode()&fs.ModeSymlink != 0 {
+		return fmt.Errorf("invalid path %s: is a symlink", full)
+	}
+
+	realRoot, err := evalExisting(root)
+	if err != nil {
+		return err
+	}
+	realFull, err := evalExisting(full)
+	if err != nil {
+		return err
+	}
+	if !inside(realRoot, realFull) {
+		return fmt.Errorf("invalid path %s: resolves to %s, outside %s", full, realFull, root)
+	}
+	return nil
+}
+
+// evalExisting resolves the symlinks of the longest existing prefix of path
+// and appends the rest.
+func evalExisting(path string) (string, error) {
+	rest := ""
+	for p := filepath.Clean(path); ; p = filepath.Dir(p) {
+		real, err := filepath.EvalSymlinks(p)
+		if err == nil {
+			return filepath.Join(real, rest), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+		if filepath.Dir(p) == p {
+			return path, nil
+		}
+		rest = filepath.Join(filepath.Base(p), rest)
+	}
+}
+
+// Within returns dir, absolute or relative to base, if it lies inside base.
+func Within(base, dir string) (string, error) {
+	rel := dir
+	if filepath.IsAbs(dir) {
+		r, err := filepath.Rel(base, dir)
+		if err != nil {
+			return "", fmt.Errorf("invalid path %q: escapes %s", dir, base)
+		}
+		rel = r
+	}
+	full, err := Resolve(base, rel)
+	if err == nil {
+		err = Contained(base, full)
+	}
+	if err != nil {
+		return "", fmt.Errorf("invalid path %q: must be inside %s", dir, base)
+	}
+	return full, nil
+}
+
+// Validate checks every path and policy in the plan without touching disk.
+func (p Plan) Validate(root string) error {
+	for _, d := range p.Dirs {
+		if _, err := Resolve(root, d); err != nil {
+			return err
+		}
+	}
+	for _, f := range p.Files {
+		if _, err := Resolve(root, f.Path); err != nil {
+			return err
+		}
+		if f.OnConflict != "" {
+			if _, err := conflict.Parse(f.OnConflict); err != nil {
+				return err
+			}
+		}
+	}
+	for _, c := range p.Commands {
+		if c.Name == "" {
+			return fmt.Errorf("command without name")
+		}
+		if c.Dir != "" && c.Dir != "." {
+			if _, err := Resolve(root, c.Dir); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Apply creates the plan's directories, files and commands under root,
+// in that order, and describes every action taken.
+func Apply(target fsys.FS, r runner.Runner, root string, p Plan) ([]string, error) {
+	if err := p.Validate(root); err != nil {
+		return nil, err
+	}
+
+	var summary []string
+
+	if err := target.MkdirAll(root, 0o755); err != nil {
+		return summary, fmt.Errorf("failed to create %s: %w", root, err)
+	}
+
+	for _, d := range p.Dirs {
+		full, _ := Resolve(root, d)
+		if err := Contained(root, full); err != nil {
+			return summary, err
+		}
+		if err := target.MkdirAll(full, 0o755); err != nil {
+			return summary, fmt.Errorf("failed to create %s/: %w", d, err)
+		}
+		summary = append(summary, "Created "+d+"/ folder")
+	}
+
+	for _, f := range p.Files {
+		full, _ := Resolve(root, f.Path)
+		if err := Contained(root, full); err != nil {
+			return summary, err
+		}
+		if err := target.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			return summary, fmt.Errorf("failed to create %s: %w", filepath.Dir(f.Path), err)
+		}
+
+		policy := conflict.Skip
+		if f.OnConflict != "" {
+			policy, _ = conflict.Parse(f.OnConflict)
+		}
+		mode := f.Mode.Perm() &^ 0o002
+		if mode == 0 {
+			mode = 0o644
+		}
+
+		action, err := conflict.Write(target, full, []byte(f.Content), mode, policy)
+		if err != nil {
+			return summary, fmt.Errorf("failed to write %s: %w", f.Path, err)
+		}
+		if action == "created" {
+			summary = append(summary, "Created "+f.Path)
+		} else {
+			summary = append(summary, fmt.Sprintf("%s %s (policy: %s)", f.Path, action, policy))
+		}
+	}
+
+	for _, c := range p.Commands {
+		dir := root
+		if c.Dir != "" && c.Dir != "." {
+			dir, _ = Resolve(root, c.Dir)
+			if err := Contained(root, dir); err != nil {
+				return summary, err
+			}
+		}
+		cmd := runner.Cmd(c.Name, c.Args...).In(dir)
+		if out, err := r.Run(cmd); err != nil {
+			return summary, fmt.Errorf("%s failed: %v\n%s", cmd, err, string(out))
+		}
+		summary = append(summary, "Ran "+cmd.String())
+	}
+
+	summary = append(summary, p.Messages...)
+
+	return summary, nil
+}
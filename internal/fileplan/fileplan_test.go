@@ -0,0 +1,129 @@
+package fileplan
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/runner"
+)
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		rel  string
+		want string
+	}{
+		{"a/b.txt", "/p/a/b.txt"},
+		{"a/../b.txt", "/p/b.txt"},
+		{".", "/p"},
+		{"", ""},
+		{"/etc/passwd", ""},
+		{"..", ""},
+		{"a/../../x", ""},
+	}
+
+	for _, tt := range tests {
+		got, err := Resolve("/p", tt.rel)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("Resolve(%q) = %q, want an error", tt.rel, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Resolve(%q) = %q, %v; want %q", tt.rel, got, err, tt.want)
+		}
+	}
+}
+
+// project returns a project directory holding a symlink "out" to a
+// directory outside it and a symlink "link.txt" to a file outside it.
+func project(t *testing.T) (root, outside string) {
+	t.Helper()
+
+	tmp := t.TempDir()
+	root = filepath.Join(tmp, "project")
+	outside = filepath.Join(tmp, "home")
+	for _, d := range []string{filepath.Join(root, "src"), outside} {
+		if err := os.MkdirAll(d, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(outside, ".bashrc"), []byte("mine"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "out")); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+	if err := os.Symlink(filepath.Join(outside, ".bashrc"), filepath.Join(root, "link.txt")); err != nil {
+		t.Fatal(err)
+	}
+	// an internal link is followed but stays inside
+	if err := os.Symlink(filepath.Join(root, "src"), filepath.Join(root, "code")); err != nil {
+		t.Fatal(err)
+	}
+	return root, outside
+}
+
+func TestApplySymlinks(t *testing.T) {
+	tests := []struct {
+		name string
+		plan Plan
+		ok   bool
+	}{
+		{"plain file", Plan{Files: []File{{Path: "src/main.go", Content: "package main\n"}}}, true},
+		{"new folder", Plan{Dirs: []string{"docs/adr"}}, true},
+		{"link inside the project", Plan{Files: []File{{Path: "code/x.go", Content: "x"}}}, true},
+		{"file through a linked folder", Plan{Files: []File{{Path: "out/.profile", Content: "x"}}}, false},
+		{"folder through a linked folder", Plan{Dirs: []string{"out/evil"}}, false},
+		{"overwriting a linked file", Plan{Files: []File{{Path: "link.txt", Content: "x", OnConflict: "overwrite"}}}, false},
+		{"command in a linked folder", Plan{Commands: []Command{{Name: "touch", Args: []string{"x"}, Dir: "out"}}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, outside := project(t)
+			r := runner.NewFake()
+
+			_, err := Apply(fsys.NewOS(), r, root, tt.plan)
+			if tt.ok && err != nil {
+				t.Fatal(err)
+			}
+			if !tt.ok && err == nil {
+				t.Fatal("Apply wrote through a symlink leaving the project")
+			}
+
+			entries, _ := os.ReadDir(outside)
+			if len(entries) != 1 {
+				t.Errorf("the outside directory holds %d entries, want only .bashrc", len(entries))
+			}
+			if data, _ := os.ReadFile(filepath.Join(outside, ".bashrc")); string(data) != "mine" {
+				t.Errorf(".bashrc = %q, want it untouched", data)
+			}
+			if !tt.ok && len(r.Calls()) != 0 {
+				t.Errorf("ran %v", r.CommandLines())
+			}
+		})
+	}
+}
+
+func TestWithin(t *testing.T) {
+	root, _ := project(t)
+
+	tests := []struct {
+		dir string
+		ok  bool
+	}{
+		{"new-service", true},
+		{filepath.Join(root, "src"), true},
+		{"out/service", false},
+		{"../elsewhere", false},
+	}
+
+	for _, tt := range tests {
+		if _, err := Within(root, tt.dir); (err == nil) != tt.ok {
+			t.Errorf("Within(%q) error = %v, want ok %v", tt.dir, err, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package fsys
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sort"
+
+	"github.com/ezeqielle/pcli/internal/plan"
+)
+
+// DryRun is an FS that never writes to its base.
+//
+// Writes are recorded into a plan and kept in an in-memory overlay, so later
+// reads and existence checks behave as if the writes had happened.
+type DryRun struct {
+	base    FS
+	overlay *Memory
+	plan    *plan.Plan
+}
+
+// NewDryRun returns a recording FS reading from base.
+func NewDryRun(base FS, p *plan.Plan) *DryRun {
+	return &DryRun{base: base, overlay: NewMemory(), plan: p}
+}
+
+// Plan returns the plan the writes are recorded into.
+func (d *DryRun) Plan() *plan.Plan {
+	return d.plan
+}
+
+func (d *DryRun) MkdirAll(path string, perm fs.FileMode) error {
+	path = filepath.Clean(path)
+
+	var missing []string
+	for p := path; !isRoot(p); p = filepath.Dir(p) {
+		if info, err := d.Stat(p); err == nil {
+			if !info.IsDir() {
+				return &fs.PathError{Op: "mkdir", Path: p, Err: fs.ErrExist}
+			}
+			break
+		}
+		missing = append(missing, p)
+	}
+
+	for i := len(missing) - 1; i >= 0; i-- {
+		d.plan.AddDir(missing[i])
+	}
+	return d.overlay.MkdirAll(path, perm)
+}
+
+func (d *DryRun) WriteFile(path string, data []byte, perm fs.FileMode) error {
+	path = filepath.Clean(path)
+
+	parent := filepath.Dir(path)
+	if info, err := d.Stat(parent); err != nil || !info.IsDir() {
+		return &fs.PathError{Op: "open", Path: path, Err: fs.ErrNotExist}
+	}
+
+	_, err := d.base.Stat(path)
+	d.plan.AddFile(path, len(data), err == nil)
+
+	if err := d.overlay.MkdirAll(parent, 0o755); err != nil {
+		return err
+	}
+	return d.overlay.WriteFile(path, data, perm)
+}
+
+func (d *DryRun) ReadFile(path string) ([]byte, error) {
+	if data, err := d.overlay.ReadFile(path); err == nil {
+		return data, nil
+	}
+	return d.base.ReadFile(path)
+}
+
+func (d *DryRun) ReadDir(path string) ([]fs.DirEntry, error) {
+	byName := map[string]fs.DirEntry{}
+
+	base, baseErr := d.base.ReadDir(path)
+	for _, e := range base {
+		byName[e.Name()] = e
+	}
+	overlay, overlayErr := d.overlay.ReadDir(path)
+	for _, e := range overlay {
+		byName[e.Name()] = e
+	}
+	if baseErr != nil && overlayErr != nil {
+		return nil, baseErr
+	}
+
+	out := make([]fs.DirEntry, 0, len(byName))
+	for _, e := range byName {
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name() < out[j].Name() })
+	return out, nil
+}
+
+// Remove only drops the path from the overlay: a dry run never deletes from disk.
+func (d *DryRun) Remove(path string) error {
+	return d.overlay.Remove(path)
+}
+
+func (d *DryRun) Stat(path string) (fs.FileInfo, error) {
+	if info, err := d.overlay.Stat(path); err == nil && !isRoot(filepath.Clean(path)) {
+		return info, nil
+	}
+	return d.base.Stat(path)
+}
+
+// PlanOf returns the plan recorded by fsys when it is, or wraps, a dry-run FS.
+func PlanOf(fsys FS) (*plan.Plan, bool) {
+	for fsys != nil {
+		if d, ok := fsys.(*DryRun); ok {
+			return d.plan, true
+		}
+		fsys = Unwrap(fsys)
+	}
+	return nil, false
+}
@@ -0,0 +1,73 @@
+package fsys
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+)
+
+// FS is the set of filesystem operations pcli uses to scaffold projects.
+//
+// Plugins never call the os package directly for project files, so the
+// same code can write to disk, to memory, or only record a plan.
+type FS interface {
+	MkdirAll(path string, perm fs.FileMode) error
+	WriteFile(path string, data []byte, perm fs.FileMode) error
+	ReadFile(path string) ([]byte, error)
+	ReadDir(path string) ([]fs.DirEntry, error)
+	Stat(path string) (fs.FileInfo, error)
+
+	// Remove deletes a file or an empty directory.
+	Remove(path string) error
+}
+
+// Unwrap returns the FS wrapped by fsys, or nil when fsys wraps nothing.
+// Wrappers expose their base through an Unwrap() FS method.
+func Unwrap(fsys FS) FS {
+	if u, ok := fsys.(interface{ Unwrap() FS }); ok {
+		return u.Unwrap()
+	}
+	return nil
+}
+
+// Exists reports whether path exists in fsys.
+func Exists(fsys FS, path string) bool {
+	_, err := fsys.Stat(path)
+	return err == nil
+}
+
+// IsNotExist reports whether err means the path does not exist.
+func IsNotExist(err error) bool {
+	return errors.Is(err, fs.ErrNotExist)
+}
+
+// OS is the FS backed by the real filesystem.
+type OS struct{}
+
+func NewOS() *OS {
+	return &OS{}
+}
+
+func (OS) MkdirAll(path string, perm fs.FileMode) error {
+	return os.MkdirAll(path, perm)
+}
+
+func (OS) WriteFile(path string, data []byte, perm fs.FileMode) error {
+	return os.WriteFile(path, data, perm)
+}
+
+func (OS) ReadFile(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+func (OS) ReadDir(path string) ([]fs.DirEntry, error) {
+	return os.ReadDir(path)
+}
+
+func (OS) Stat(path string) (fs.FileInfo, error) {
+	return os.Stat(path)
+}
+
+func (OS) Remove(path string) error {
+	return os.Remove(path)
+}
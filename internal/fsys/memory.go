@@ -0,0 +1,181 @@
+package fsys
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+type memEntry struct {
+	dir     bool
+	data    []byte
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+// Memory is an in-memory FS. The root and the working directory "." always exist.
+type Memory struct {
+	mu      sync.RWMutex
+	entries map[string]*memEntry
+}
+
+func NewMemory() *Memory {
+	return &Memory{entries: map[string]*memEntry{}}
+}
+
+func (m *Memory) MkdirAll(path string, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	path = filepath.Clean(path)
+	var missing []string
+	for p := path; !isRoot(p); p = filepath.Dir(p) {
+		e, ok := m.entries[p]
+		if ok {
+			if !e.dir {
+				return &fs.PathError{Op: "mkdir", Path: p, Err: fs.ErrExist}
+			}
+			break
+		}
+		missing = append(missing, p)
+	}
+
+	now := time.Now()
+	for i := len(missing) - 1; i >= 0; i-- {
+		m.entries[missing[i]] = &memEntry{dir: true, mode: fs.ModeDir | perm, modTime: now}
+	}
+	return nil
+}
+
+func (m *Memory) WriteFile(path string, data []byte, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	path = filepath.Clean(path)
+	if parent := filepath.Dir(path); !isRoot(parent) {
+		e, ok := m.entries[parent]
+		if !ok || !e.dir {
+			return &fs.PathError{Op: "open", Path: path, Err: fs.ErrNotExist}
+		}
+	}
+	if e, ok := m.entries[path]; ok && e.dir {
+		return &fs.PathError{Op: "open", Path: path, Err: fs.ErrInvalid}
+	}
+
+	buf := make([]byte, len(data))
+	copy(buf, data)
+	m.entries[path] = &memEntry{data: buf, mode: perm, modTime: time.Now()}
+	return nil
+}
+
+func (m *Memory) ReadFile(path string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	path = filepath.Clean(path)
+	e, ok := m.entries[path]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: path, Err: fs.ErrNotExist}
+	}
+	if e.dir {
+		return nil, &fs.PathError{Op: "read", Path: path, Err: fs.ErrInvalid}
+	}
+
+	out := make([]byte, len(e.data))
+	copy(out, e.data)
+	return out, nil
+}
+
+func (m *Memory) ReadDir(path string) ([]fs.DirEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	path = filepath.Clean(path)
+	if !isRoot(path) {
+		e, ok := m.entries[path]
+		if !ok {
+			return nil, &fs.PathError{Op: "open", Path: path, Err: fs.ErrNotExist}
+		}
+		if !e.dir {
+			return nil, &fs.PathError{Op: "readdir", Path: path, Err: fs.ErrInvalid}
+		}
+	}
+
+	var out []fs.DirEntry
+	for p, e := range m.entries {
+		if filepath.Dir(p) == path && p != path {
+			out = append(out, fs.FileInfoToDirEntry(memInfo{name: filepath.Base(p), entry: e}))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name() < out[j].Name() })
+	return out, nil
+}
+
+func (m *Memory) Remove(path string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	path = filepath.Clean(path)
+	e, ok := m.entries[path]
+	if !ok {
+		return &fs.PathError{Op: "remove", Path: path, Err: fs.ErrNotExist}
+	}
+	if e.dir {
+		for p := range m.entries {
+			if filepath.Dir(p) == path && p != path {
+				return &fs.PathError{Op: "remove", Path: path, Err: errNotEmpty}
+			}
+		}
+	}
+	delete(m.entries, path)
+	return nil
+}
+
+func (m *Memory) Stat(path string) (fs.FileInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	path = filepath.Clean(path)
+	if isRoot(path) {
+		return memInfo{name: path, entry: &memEntry{dir: true, mode: fs.ModeDir | 0o755}}, nil
+	}
+	e, ok := m.entries[path]
+	if !ok {
+		return nil, &fs.PathError{Op: "stat", Path: path, Err: fs.ErrNotExist}
+	}
+	return memInfo{name: filepath.Base(path), entry: e}, nil
+}
+
+// Paths returns every file and directory held in memory, sorted.
+func (m *Memory) Paths() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	out := make([]string, 0, len(m.entries))
+	for p := range m.entries {
+		out = append(out, p)
+	}
+	sort.Strings(out)
+	return out
+}
+
+var errNotEmpty = errors.New("directory not empty")
+
+func isRoot(path string) bool {
+	return path == "." || path == string(filepath.Separator) || filepath.Dir(path) == path
+}
+
+type memInfo struct {
+	name  string
+	entry *memEntry
+}
+
+func (i memInfo) Name() string       { return i.name }
+func (i memInfo) Size() int64        { return int64(len(i.entry.data)) }
+func (i memInfo) Mode() fs.FileMode  { return i.entry.mode }
+func (i memInfo) ModTime() time.Time { return i.entry.modTime }
+func (i memInfo) IsDir() bool        { return i.entry.dir }
+func (i memInfo) Sys() any           { return nil }
@@ -0,0 +1,126 @@
+package gitignore
+
+import (
+	"embed"
+	"fmt"
+	"strings"
+)
+
+//go:embed fragments
+var fragments embed.FS
+
+// Kind groups the fragments in the wizard.
+type Kind string
+
+const (
+	Project  Kind = "project"
+	Language Kind = "language"
+	Tool     Kind = "tool"
+	Editor   Kind = "editor"
+	OS       Kind = "os"
+)
+
+// Fragment is a reusable piece of .gitignore, stored as
+// fragments/<ID>.gitignore.
+type Fragment struct {
+	ID    string
+	Kind  Kind
+	Label string
+}
+
+var library = []Fragment{
+	{ID: "project", Kind: Project, Label: "Local settings and notes (.env, notes/)"},
+	{ID: "go", Kind: Language, Label: "Go"},
+	{ID: "node", Kind: Language, Label: "Node.js / TypeScript"},
+	{ID: "python", Kind: Language, Label: "Python"},
+	{ID: "rust", Kind: Language, Label: "Rust"},
+	{ID: "java", Kind: Language, Label: "Java (Maven, Gradle)"},
+	{ID: "terraform", Kind: Tool, Label: "Terraform"},
+	{ID: "docker", Kind: Tool, Label: "Docker compose overrides"},
+	{ID: "nix", Kind: Tool, Label: "Nix and direnv"},
+	{ID: "vscode", Kind: Editor, Label: "VS Code"},
+	{ID: "jetbrains", Kind: Editor, Label: "JetBrains IDEs"},
+	{ID: "vim", Kind: Editor, Label: "Vim"},
+	{ID: "emacs", Kind: Editor, Label: "Emacs"},
+	{ID: "macos", Kind: OS, Label: "macOS"},
+	{ID: "windows", Kind: OS, Label: "Windows"},
+	{ID: "linux", Kind: OS, Label: "Linux"},
+}
+
+// Fragments returns the library in display order.
+func Fragments() []Fragment {
+	return append([]Fragment(nil), library...)
+}
+
+// typeFragments maps project types to the fragments they always get.
+var typeFragments = map[string][]string{
+	"go":         {"go"},
+	"node":       {"node"},
+	"javascript": {"node"},
+	"typescript": {"node"},
+	"python":     {"python"},
+	"rust":       {"rust"},
+	"java":       {"java"},
+	"terraform":  {"terraform"},
+}
+
+// ForType returns the fragments a project of projectType starts with.
+func ForType(projectType string) []string {
+	return append([]string{"project"}, typeFragments[projectType]...)
+}
+
+// Lookup returns the fragment with the given ID.
+func Lookup(id string) (Fragment, bool) {
+	for _, f := range library {
+		if f.ID == id {
+			return f, true
+		}
+	}
+	return Fragment{}, false
+}
+
+// Compose concatenates the fragments in order. A pattern already emitted by
+// an earlier fragment is dropped, and so is a section left without
+// patterns; comments stay with the patterns they introduce.
+func Compose(ids []string) ([]byte, error) {
+	seen := map[string]bool{}
+	var sections []string
+
+	for _, id := range ids {
+		if seen["fragment:"+id] {
+			continue
+		}
+		seen["fragment:"+id] = true
+
+		data, err := fragments.ReadFile("fragments/" + id + ".gitignore")
+		if err != nil {
+			return nil, fmt.Errorf("unknown gitignore fragment %q", id)
+		}
+
+		for _, block := range strings.Split(strings.TrimSpace(string(data)), "\n\n") {
+			var lines []string
+			patterns := 0
+			for _, line := range strings.Split(block, "\n") {
+				key := strings.TrimSpace(line)
+				if strings.HasPrefix(key, "#") {
+					lines = append(lines, line)
+					continue
+				}
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				lines = append(lines, line)
+				patterns++
+			}
+			if patterns > 0 {
+				sections = append(sections, strings.Join(lines, "\n"))
+			}
+		}
+	}
+
+	if len(sections) == 0 {
+		return nil, nil
+	}
+	return []byte(strings.Join(sections, "\n\n") + "\n"), nil
+}
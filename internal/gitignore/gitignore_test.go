@@ -0,0 +1,115 @@
+package gitignore
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompose(t *testing.T) {
+	tests := []struct {
+		name string
+		ids  []string
+		want string
+	}{
+		{
+			name: "single fragment",
+			ids:  []string{"docker"},
+			want: "# Local compose overrides\ncompose.override.yaml\ndocker-compose.override.yml\n",
+		},
+		{
+			name: "fragments in order",
+			ids:  []string{"nix", "docker"},
+			want: "# Nix build results and direnv\nresult\nresult-*\n.direnv/\n\n" +
+				"# Local compose overrides\ncompose.override.yaml\ndocker-compose.override.yml\n",
+		},
+		{
+			name: "repeated fragment",
+			ids:  []string{"docker", "docker"},
+			want: "# Local compose overrides\ncompose.override.yaml\ndocker-compose.override.yml\n",
+		},
+		{
+			name: "section left without patterns",
+			ids:  []string{"java", "rust"},
+			want: "# Compiled classes and archives\n*.class\n*.jar\n*.war\n\n" +
+				"# Build output\ntarget/\nbuild/\n.gradle/\n\n" +
+				"# Backup files from rustfmt\n**/*.rs.bk\n",
+		},
+		{
+			name: "nothing",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Compose(tt.ids)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Compose(%q) =\n%s\nwant\n%s", tt.ids, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComposeKeepsCommentsWithPatterns(t *testing.T) {
+	got, err := Compose([]string{"node", "python"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// build/ and dist/ come from node; python's packaging section keeps
+	// its comment for the one pattern left
+	if want := "\n\n# Packaging\n*.egg-info/\n\n"; !strings.Contains(string(got), want) {
+		t.Errorf("Compose =\n%s\nwant it to contain %q", got, want)
+	}
+	for _, line := range []string{"build/", "dist/"} {
+		if n := strings.Count(string(got), "\n"+line+"\n"); n != 1 {
+			t.Errorf("%s appears %d times", line, n)
+		}
+	}
+}
+
+func TestComposeUnknown(t *testing.T) {
+	if _, err := Compose([]string{"go", "cobol"}); err == nil || !strings.Contains(err.Error(), `"cobol"`) {
+		t.Errorf("Compose with an unknown fragment: %v", err)
+	}
+}
+
+func TestLibrary(t *testing.T) {
+	for _, f := range Fragments() {
+		data, err := Compose([]string{f.ID})
+		if err != nil || len(data) == 0 {
+			t.Errorf("fragment %s: %q, %v", f.ID, data, err)
+		}
+		if got, ok := Lookup(f.ID); !ok || got != f {
+			t.Errorf("Lookup(%q) = %+v, %v", f.ID, got, ok)
+		}
+	}
+
+	for typ, ids := range typeFragments {
+		for _, id := range ids {
+			if _, ok := Lookup(id); !ok {
+				t.Errorf("type %s uses unknown fragment %q", typ, id)
+			}
+		}
+	}
+}
+
+func TestForType(t *testing.T) {
+	tests := []struct {
+		projectType string
+		want        string
+	}{
+		{"go", "project,go"},
+		{"typescript", "project,node"},
+		{"unknown", "project"},
+	}
+
+	for _, tt := range tests {
+		if got := strings.Join(ForType(tt.projectType), ","); got != tt.want {
+			t.Errorf("ForType(%q) = %s, want %s", tt.projectType, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package gomod
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ezeqielle/pcli/internal/fsys"
+)
+
+// Info is what pcli reads from a project's go.mod.
+type Info struct {
+	Module string
+	// GoVersion is the go directive, e.g. 1.24.2; empty when missing.
+	GoVersion string
+	// Toolchain is the toolchain directive, e.g. go1.24.3; empty when missing.
+	Toolchain string
+	// Tools are the packages of the tool directives (Go 1.24+).
+	Tools []string
+}
+
+// Read parses dir/go.mod.
+func Read(fs fsys.FS, dir string) (Info, error) {
+	data, err := fs.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return Info{}, fmt.Errorf("failed to read go.mod: %w", err)
+	}
+	return Parse(data), nil
+}
+
+// Parse reads the module, go, toolchain and tool directives.
+// It is not a full go.mod parser: requirements and replacements are ignored.
+func Parse(data []byte) Info {
+	var info Info
+	inTools := false
+	for _, line := range strings.Split(string(data), "\n") {
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+
+		if inTools {
+			switch {
+			case len(fields) == 1 && fields[0] == ")":
+				inTools = false
+			case len(fields) == 1:
+				info.Tools = append(info.Tools, strings.Trim(fields[0], `"`))
+			}
+			continue
+		}
+		if len(fields) != 2 {
+			continue
+		}
+
+		val := strings.Trim(fields[1], `"`)
+		switch fields[0] {
+		case "module":
+			info.Module = val
+		case "go":
+			info.GoVersion = val
+		case "toolchain":
+			info.Toolchain = val
+		case "tool":
+			if val == "(" {
+				inTools = true
+			} else {
+				info.Tools = append(info.Tools, val)
+			}
+		}
+	}
+	return info
+}
+
+// Binary returns the name go build gives the module's main package: the
+// last element of the module path, skipping a major version suffix like
+// /v2.
+func (i Info) Binary() string {
+	elems := strings.Split(i.Module, "/")
+	last := elems[len(elems)-1]
+	if len(elems) > 1 && isMajorVersion(last) {
+		last = elems[len(elems)-2]
+	}
+	return last
+}
+
+// isMajorVersion reports whether elem is a module major version suffix, v2
+// or later.
+func isMajorVersion(elem string) bool {
+	if len(elem) < 2 || elem[0] != 'v' {
+		return false
+	}
+	n, err := strconv.Atoi(elem[1:])
+	return err == nil && n >= 2 && elem[1] != '0'
+}
+
+// MinorVersion returns the go directive without its patch release, e.g.
+// 1.24 for 1.24.2; empty when the directive is missing.
+func (i Info) MinorVersion() string {
+	parts := strings.SplitN(i.GoVersion, ".", 3)
+	if len(parts) < 2 {
+		return i.GoVersion
+	}
+	return parts[0] + "." + parts[1]
+}
+
+// AtLeast reports whether the go directive is major.minor or later; a
+// missing directive counts as older.
+func (i Info) AtLeast(major, minor int) bool {
+	return i.Supports(fmt.Sprintf("%d.%d", major, minor))
+}
+
+// Supports reports whether the go directive is goVersion or later, i.e.
+// whether a module declaring go goVersion can be required without raising
+// it. An empty goVersion is always supported; a missing directive counts as
+// older than any version.
+func (i Info) Supports(goVersion string) bool {
+	want, ok := parseGoVersion(goVersion)
+	if !ok {
+		return goVersion == ""
+	}
+	have, ok := parseGoVersion(i.GoVersion)
+	if !ok {
+		return false
+	}
+	for n := range have {
+		if have[n] != want[n] {
+			return have[n] > want[n]
+		}
+	}
+	return true
+}
+
+// parseGoVersion splits a go version like 1.24.2 into its numbers; the
+// patch release defaults to 0 and pre-releases like 1.25rc1 count as their
+// release.
+func parseGoVersion(v string) ([3]int, bool) {
+	var out [3]int
+	parts := strings.SplitN(v, ".", 3)
+	if len(parts) < 2 {
+		return out, false
+	}
+	for n, part := range parts {
+		if end := strings.IndexFunc(part, func(r rune) bool { return r < '0' || r > '9' }); end >= 0 {
+			part = part[:end]
+		}
+		num, err := strconv.Atoi(part)
+		if err != nil {
+			return out, false
+		}
+		out[n] = num
+	}
+	return out, true
+}
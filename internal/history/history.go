@@ -0,0 +1,279 @@
+package history
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/journal"
+)
+
+// File is a file written by a run.
+type File struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+	// Existed is true when the file was already there and pcli overwrote it.
+	// Undo never deletes such files.
+	Existed bool `json:"existed,omitempty"`
+}
+
+// Record is one project creation run.
+type Record struct {
+	ID          int       `json:"id"`
+	Timestamp   time.Time `json:"timestamp"`
+	ProjectType string    `json:"type"`
+	Module      string    `json:"module,omitempty"`
+	Dir         string    `json:"dir"`
+	Plugins     []string  `json:"plugins,omitempty"`
+	Files       []File    `json:"files"`
+	Dirs        []string  `json:"dirs"`
+	Version     string    `json:"version"`
+
+	UndoneAt *time.Time `json:"undone_at,omitempty"`
+}
+
+// FromJournal builds a record from a completed run, hashing every file it wrote.
+func FromJournal(j *journal.Journal, fs fsys.FS, version string) (Record, error) {
+	info := j.Info()
+
+	rec := Record{
+		Timestamp:   time.Now(),
+		ProjectType: info.ProjectType,
+		Module:      info.Module,
+		Dir:         info.Dir,
+		Plugins:     info.Plugins,
+		Version:     version,
+	}
+
+	seen := map[string]bool{}
+	for _, e := range j.Entries() {
+		switch e.Kind {
+		case journal.KindDir:
+			rec.Dirs = append(rec.Dirs, e.Path)
+
+		case journal.KindFile, journal.KindOverwrite:
+			if seen[e.Path] {
+				continue
+			}
+			seen[e.Path] = true
+
+			sum, err := hashFile(fs, e.Path)
+			if err != nil {
+				if fsys.IsNotExist(err) {
+					continue
+				}
+				return rec, err
+			}
+			rec.Files = append(rec.Files, File{Path: e.Path, SHA256: sum, Existed: e.Kind == journal.KindOverwrite})
+		}
+	}
+
+	return rec, nil
+}
+
+func hashFile(fs fsys.FS, path string) (string, error) {
+	data, err := fs.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// DefaultPath returns $XDG_STATE_HOME/pcli/history.json,
+// falling back to ~/.local/state/pcli/history.json.
+func DefaultPath() (string, error) {
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" && filepath.IsAbs(dir) {
+		return filepath.Join(dir, "pcli", "history.json"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate home directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "state", "pcli", "history.json"), nil
+}
+
+// Store persists records as a JSON array.
+type Store struct {
+	fs   fsys.FS
+	path string
+}
+
+func NewStore(fs fsys.FS, path string) *Store {
+	return &Store{fs: fs, path: path}
+}
+
+// DefaultStore returns the on-disk store at DefaultPath.
+func DefaultStore() (*Store, error) {
+	path, err := DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return NewStore(fsys.NewOS(), path), nil
+}
+
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Load returns every record, oldest first. A missing file yields no records.
+func (s *Store) Load() ([]Record, error) {
+	data, err := s.fs.ReadFile(s.path)
+	if err != nil {
+		if fsys.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	var records []Record
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse history %s: %w", s.path, err)
+	}
+	return records, nil
+}
+
+func (s *Store) save(records []Record) error {
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := s.fs.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+	if err := s.fs.WriteFile(s.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return nil
+}
+
+// Add assigns the next ID to rec and appends it.
+func (s *Store) Add(rec Record) (Record, error) {
+	records, err := s.Load()
+	if err != nil {
+		return rec, err
+	}
+
+	rec.ID = 1
+	for _, r := range records {
+		if r.ID >= rec.ID {
+			rec.ID = r.ID + 1
+		}
+	}
+
+	records = append(records, rec)
+	return rec, s.save(records)
+}
+
+// RecentTypes returns the project types of the latest runs, newest first
+// and without duplicates, at most n of them.
+func (s *Store) RecentTypes(n int) ([]string, error) {
+	records, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	var types []string
+	seen := map[string]bool{}
+	for i := len(records) - 1; i >= 0 && len(types) < n; i-- {
+		t := records[i].ProjectType
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		types = append(types, t)
+	}
+	return types, nil
+}
+
+// Get returns the record with the given ID.
+func (s *Store) Get(id int) (Record, error) {
+	records, err := s.Load()
+	if err != nil {
+		return Record{}, err
+	}
+	for _, r := range records {
+		if r.ID == id {
+			return r, nil
+		}
+	}
+	return Record{}, fmt.Errorf("no history entry with id %d", id)
+}
+
+// Undo removes the files and directories recorded for run id from target.
+//
+// Files whose content changed since the run (by SHA-256) are kept, as are files
+// that existed before the run and directories that are no longer empty.
+// The returned lines describe what happened to each path.
+func (s *Store) Undo(target fsys.FS, id int) ([]string, error) {
+	records, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	idx := -1
+	for i, r := range records {
+		if r.ID == id {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return nil, fmt.Errorf("no history entry with id %d", id)
+	}
+
+	rec := records[idx]
+	if rec.UndoneAt != nil {
+		return nil, fmt.Errorf("history entry %d was already undone on %s", id, rec.UndoneAt.Format(time.DateTime))
+	}
+
+	var summary []string
+	for i := len(rec.Files) - 1; i >= 0; i-- {
+		f := rec.Files[i]
+
+		if f.Existed {
+			summary = append(summary, "Kept "+f.Path+" (existed before pcli)")
+			continue
+		}
+
+		sum, err := hashFile(target, f.Path)
+		if err != nil {
+			if fsys.IsNotExist(err) {
+				summary = append(summary, "Already gone "+f.Path)
+				continue
+			}
+			return summary, err
+		}
+		if sum != f.SHA256 {
+			summary = append(summary, "Kept "+f.Path+" (modified since creation)")
+			continue
+		}
+
+		if err := target.Remove(f.Path); err != nil {
+			return summary, fmt.Errorf("failed to remove %s: %w", f.Path, err)
+		}
+		summary = append(summary, "Removed "+f.Path)
+	}
+
+	for i := len(rec.Dirs) - 1; i >= 0; i-- {
+		d := rec.Dirs[i]
+		if err := target.Remove(d); err != nil {
+			if !fsys.IsNotExist(err) {
+				summary = append(summary, "Kept "+d+"/ (not empty)")
+			}
+			continue
+		}
+		summary = append(summary, "Removed "+d+"/")
+	}
+
+	now := time.Now()
+	records[idx].UndoneAt = &now
+
+	return summary, s.save(records)
+}
@@ -0,0 +1,202 @@
+package history
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/journal"
+)
+
+func sum(data string) string {
+	s := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(s[:])
+}
+
+// record journals a small project creation on fs and stores it: /p/app/go.mod
+// and /p/app/cmd/main.go are created, the existing /p/app/.env overwritten.
+func record(t *testing.T, fs fsys.FS, store *Store) Record {
+	t.Helper()
+
+	if err := fs.MkdirAll("/p/app", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.WriteFile("/p/app/.env", []byte("A=1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	j := journal.New(fs)
+	j.SetProject("go", "example.com/app", "/p/app")
+	jfs := j.FS()
+	if err := jfs.MkdirAll("/p/app/cmd", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []struct{ path, data string }{
+		{"/p/app/go.mod", "module app\n"},
+		{"/p/app/cmd/main.go", "package main\n"},
+		{"/p/app/.env", "A=2\n"},
+		{"/p/app/.env", "A=3\n"},
+	} {
+		if err := jfs.WriteFile(f.path, []byte(f.data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rec, err := FromJournal(j, fs, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec, err = store.Add(rec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rec
+}
+
+func TestFromJournal(t *testing.T) {
+	fs := fsys.NewMemory()
+	rec := record(t, fs, NewStore(fs, "/state/history.json"))
+
+	want := []File{
+		{Path: "/p/app/go.mod", SHA256: sum("module app\n")},
+		{Path: "/p/app/cmd/main.go", SHA256: sum("package main\n")},
+		{Path: "/p/app/.env", SHA256: sum("A=3\n"), Existed: true},
+	}
+	if !reflect.DeepEqual(rec.Files, want) {
+		t.Errorf("files = %+v, want %+v", rec.Files, want)
+	}
+	if want := []string{"/p/app/cmd"}; !reflect.DeepEqual(rec.Dirs, want) {
+		t.Errorf("dirs = %q, want %q", rec.Dirs, want)
+	}
+	if rec.ID != 1 || rec.ProjectType != "go" || rec.Module != "example.com/app" || rec.Version != "test" {
+		t.Errorf("record = %+v", rec)
+	}
+}
+
+func TestUndo(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(fsys.FS) error
+		want   []string
+		left   []string
+	}{
+		{
+			name: "unchanged",
+			want: []string{
+				"Kept /p/app/.env (existed before pcli)",
+				"Removed /p/app/cmd/main.go",
+				"Removed /p/app/go.mod",
+				"Removed /p/app/cmd/",
+			},
+			left: []string{"/p", "/p/app", "/p/app/.env"},
+		},
+		{
+			name: "modified file",
+			change: func(fs fsys.FS) error {
+				return fs.WriteFile("/p/app/go.mod", []byte("module app\n\ngo 1.25\n"), 0o644)
+			},
+			want: []string{
+				"Kept /p/app/.env (existed before pcli)",
+				"Removed /p/app/cmd/main.go",
+				"Kept /p/app/go.mod (modified since creation)",
+				"Removed /p/app/cmd/",
+			},
+			left: []string{"/p", "/p/app", "/p/app/.env", "/p/app/go.mod"},
+		},
+		{
+			name:   "deleted file",
+			change: func(fs fsys.FS) error { return fs.Remove("/p/app/go.mod") },
+			want: []string{
+				"Kept /p/app/.env (existed before pcli)",
+				"Removed /p/app/cmd/main.go",
+				"Already gone /p/app/go.mod",
+				"Removed /p/app/cmd/",
+			},
+			left: []string{"/p", "/p/app", "/p/app/.env"},
+		},
+		{
+			name: "user file in a created directory",
+			change: func(fs fsys.FS) error {
+				return fs.WriteFile("/p/app/cmd/tool.go", []byte("package main\n"), 0o644)
+			},
+			want: []string{
+				"Kept /p/app/.env (existed before pcli)",
+				"Removed /p/app/cmd/main.go",
+				"Removed /p/app/go.mod",
+				"Kept /p/app/cmd/ (not empty)",
+			},
+			left: []string{"/p", "/p/app", "/p/app/.env", "/p/app/cmd", "/p/app/cmd/tool.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := fsys.NewMemory()
+			store := NewStore(fsys.NewMemory(), "/state/history.json")
+			rec := record(t, fs, store)
+			if tt.change != nil {
+				if err := tt.change(fs); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			summary, err := store.Undo(fs, rec.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(summary, tt.want) {
+				t.Errorf("summary = %q, want %q", summary, tt.want)
+			}
+			if got := fs.Paths(); !reflect.DeepEqual(got, tt.left) {
+				t.Errorf("left %q, want %q", got, tt.left)
+			}
+			if data, _ := fs.ReadFile("/p/app/.env"); string(data) != "A=3\n" {
+				t.Errorf(".env = %q, want it untouched", data)
+			}
+
+			got, err := store.Get(rec.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.UndoneAt == nil {
+				t.Error("the record is not marked as undone")
+			}
+		})
+	}
+}
+
+func TestUndoErrors(t *testing.T) {
+	fs := fsys.NewMemory()
+	store := NewStore(fsys.NewMemory(), "/state/history.json")
+	rec := record(t, fs, store)
+
+	if _, err := store.Undo(fs, rec.ID+1); err == nil || !strings.Contains(err.Error(), "no history entry") {
+		t.Errorf("undo of an unknown id: %v", err)
+	}
+	if _, err := store.Undo(fs, rec.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Undo(fs, rec.ID); err == nil || !strings.Contains(err.Error(), "already undone") {
+		t.Errorf("second undo: %v", err)
+	}
+}
+
+func TestRecentTypes(t *testing.T) {
+	store := NewStore(fsys.NewMemory(), "/state/history.json")
+	for _, typ := range []string{"go", "ts", "", "go", "rust"} {
+		if _, err := store.Add(Record{ProjectType: typ}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := store.RecentTypes(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"rust", "go", "ts"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RecentTypes = %q, want %q", got, want)
+	}
+}
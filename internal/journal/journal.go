@@ -0,0 +1,378 @@
+package journal
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/runner"
+)
+
+type Kind int
+
+const (
+	// KindDir is a directory that did not exist before the run.
+	KindDir Kind = iota
+	// KindFile is a file that did not exist before the run.
+	KindFile
+	// KindOverwrite is a pre-existing file whose previous content is kept for rollback.
+	KindOverwrite
+	// KindCommand is an executed command. Commands cannot be undone, but the
+	// files they created are journaled as KindDir/KindFile entries.
+	KindCommand
+)
+
+// Entry is a single journaled change.
+type Entry struct {
+	Kind Kind
+	Path string
+
+	Previous     []byte
+	PreviousMode fs.FileMode
+
+	Command string
+	Dir     string
+}
+
+func (e Entry) String() string {
+	switch e.Kind {
+	case KindDir:
+		return "created dir  " + e.Path
+	case KindFile:
+		return "created file " + e.Path
+	case KindOverwrite:
+		return "overwrote    " + e.Path
+	case KindCommand:
+		return "ran          " + e.Command
+	}
+	return ""
+}
+
+// Journal records every directory, file and command a run creates so the run
+// can be rolled back, deleting only what pcli itself created.
+//
+// Use FS and Runner to get journaling wrappers around the real implementations.
+type Journal struct {
+	mu      sync.Mutex
+	base    fsys.FS
+	entries []Entry
+	created map[string]bool
+	// overwritten keeps the first overwrite of a path, which holds the
+	// content from before the run.
+	overwritten map[string]bool
+	info        Info
+}
+
+// Info describes the project a run created. Plugins fill it in as they go
+// so the run can be written to the creation history once it completes.
+type Info struct {
+	ProjectType string
+	Module      string
+	Dir         string
+	Plugins     []string
+}
+
+// SetProject records the project created by a project type plugin.
+func (j *Journal) SetProject(projectType, module, dir string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.info.ProjectType = projectType
+	j.info.Module = module
+	j.info.Dir = dir
+}
+
+// AddPlugin records that a post-create plugin was applied.
+func (j *Journal) AddPlugin(id string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for _, p := range j.info.Plugins {
+		if p == id {
+			return
+		}
+	}
+	j.info.Plugins = append(j.info.Plugins, id)
+}
+
+// Info returns what plugins reported about the run.
+func (j *Journal) Info() Info {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	info := j.info
+	info.Plugins = append([]string(nil), j.info.Plugins...)
+	return info
+}
+
+// New returns an empty journal whose rollback operates on base.
+func New(base fsys.FS) *Journal {
+	return &Journal{base: base, created: map[string]bool{}, overwritten: map[string]bool{}}
+}
+
+// Entries returns every journaled change, in order.
+func (j *Journal) Entries() []Entry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	out := make([]Entry, len(j.entries))
+	copy(out, j.entries)
+	return out
+}
+
+// HasChanges reports whether the journal holds anything rollback can undo.
+func (j *Journal) HasChanges() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for _, e := range j.entries {
+		if e.Kind != KindCommand {
+			return true
+		}
+	}
+	return false
+}
+
+// Created returns the paths of the files created during the run. Files
+// that existed before and were overwritten are not included.
+func (j *Journal) Created() []string {
+	var out []string
+	for _, e := range j.Entries() {
+		if e.Kind == KindFile {
+			out = append(out, e.Path)
+		}
+	}
+	return out
+}
+
+// Reset forgets every entry, e.g. once the run has completed successfully.
+func (j *Journal) Reset() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.entries = nil
+	j.created = map[string]bool{}
+	j.overwritten = map[string]bool{}
+	j.info = Info{}
+}
+
+func (j *Journal) isCreated(path string) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.created[path]
+}
+
+func (j *Journal) record(e Entry) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if e.Kind != KindCommand {
+		if j.created[e.Path] || j.overwritten[e.Path] {
+			return
+		}
+		if e.Kind == KindOverwrite {
+			j.overwritten[e.Path] = true
+		} else {
+			j.created[e.Path] = true
+		}
+	}
+	j.entries = append(j.entries, e)
+}
+
+// Rollback undoes the journaled changes in reverse order: created files and
+// directories are removed and overwritten files get their previous content back.
+// Directories that are no longer empty are kept. The journal is reset afterwards.
+func (j *Journal) Rollback() ([]string, error) {
+	entries := j.Entries()
+
+	var summary []string
+	var errs []error
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+
+		switch e.Kind {
+		case KindFile:
+			if err := j.base.Remove(e.Path); err != nil && !fsys.IsNotExist(err) {
+				errs = append(errs, fmt.Errorf("failed to remove %s: %w", e.Path, err))
+				continue
+			}
+			summary = append(summary, "Removed "+e.Path)
+
+		case KindDir:
+			if err := j.base.Remove(e.Path); err != nil {
+				if fsys.IsNotExist(err) {
+					continue
+				}
+				summary = append(summary, "Kept "+e.Path+" (not empty)")
+				continue
+			}
+			summary = append(summary, "Removed "+e.Path+"/")
+
+		case KindOverwrite:
+			if err := j.base.WriteFile(e.Path, e.Previous, e.PreviousMode); err != nil {
+				errs = append(errs, fmt.Errorf("failed to restore %s: %w", e.Path, err))
+				continue
+			}
+			summary = append(summary, "Restored "+e.Path)
+		}
+	}
+
+	j.Reset()
+
+	return summary, errors.Join(errs...)
+}
+
+// FS returns an FS that journals writes made through it.
+func (j *Journal) FS() fsys.FS {
+	return &journalFS{FS: j.base, journal: j}
+}
+
+// Runner returns a Runner that journals commands and the files they create
+// inside their working directory.
+func (j *Journal) Runner(r runner.Runner) runner.Runner {
+	return &journalRunner{Runner: r, journal: j}
+}
+
+// Of returns the journal behind fs, or nil when fs is not journaled.
+func Of(fs fsys.FS) *Journal {
+	if jf, ok := fs.(*journalFS); ok {
+		return jf.journal
+	}
+	return nil
+}
+
+type journalFS struct {
+	fsys.FS
+	journal *Journal
+}
+
+func (f *journalFS) Unwrap() fsys.FS {
+	return f.FS
+}
+
+func (f *journalFS) MkdirAll(path string, perm fs.FileMode) error {
+	path = filepath.Clean(path)
+
+	var missing []string
+	for p := path; filepath.Dir(p) != p && p != "."; p = filepath.Dir(p) {
+		if fsys.Exists(f.FS, p) {
+			break
+		}
+		missing = append(missing, p)
+	}
+
+	if err := f.FS.MkdirAll(path, perm); err != nil {
+		return err
+	}
+
+	for i := len(missing) - 1; i >= 0; i-- {
+		f.journal.record(Entry{Kind: KindDir, Path: missing[i]})
+	}
+	return nil
+}
+
+func (f *journalFS) WriteFile(path string, data []byte, perm fs.FileMode) error {
+	path = filepath.Clean(path)
+
+	entry := Entry{Kind: KindFile, Path: path}
+	if info, err := f.FS.Stat(path); err == nil && !info.IsDir() {
+		prev, err := f.FS.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		entry = Entry{Kind: KindOverwrite, Path: path, Previous: prev, PreviousMode: info.Mode().Perm()}
+	}
+
+	if err := f.FS.WriteFile(path, data, perm); err != nil {
+		return err
+	}
+
+	f.journal.record(entry)
+	return nil
+}
+
+type journalRunner struct {
+	runner.Runner
+	journal *Journal
+}
+
+func (r *journalRunner) Run(cmd runner.Command) ([]byte, error) {
+	before := r.snapshot(cmd.Dir)
+	out, err := r.Runner.Run(cmd)
+	r.recordCommand(cmd, before)
+	return out, err
+}
+
+func (r *journalRunner) Stream(cmd runner.Command, ch chan<- string) error {
+	before := r.snapshot(cmd.Dir)
+	err := r.Runner.Stream(cmd, ch)
+	r.recordCommand(cmd, before)
+	return err
+}
+
+// snapshotSkip names the directories a snapshot does not descend into when
+// they existed before the run: their content belongs to git or the package
+// manager, and walking them around every command is slow in large
+// repositories.
+var snapshotSkip = map[string]bool{".git": true, "vendor": true, "node_modules": true}
+
+func (r *journalRunner) snapshot(dir string) map[string]bool {
+	if dir == "" {
+		return nil
+	}
+
+	out := map[string]bool{}
+	for _, p := range r.walk(dir, nil) {
+		out[p] = true
+	}
+	return out
+}
+
+// walk lists the paths under dir. Directories in snapshotSkip are only
+// descended into when the run created them: the journal recorded them, or
+// before is set and does not contain them.
+func (r *journalRunner) walk(dir string, before map[string]bool) []string {
+	entries, err := r.journal.base.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var out []string
+	for _, e := range entries {
+		p := filepath.Join(dir, e.Name())
+		out = append(out, p)
+		if !e.IsDir() {
+			continue
+		}
+		if snapshotSkip[e.Name()] && (before == nil || before[p]) && !r.journal.isCreated(p) {
+			continue
+		}
+		out = append(out, r.walk(p, before)...)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func (r *journalRunner) recordCommand(cmd runner.Command, before map[string]bool) {
+	r.journal.record(Entry{Kind: KindCommand, Command: cmd.String(), Dir: cmd.Dir})
+
+	if before == nil {
+		return
+	}
+	for _, p := range r.walk(cmd.Dir, before) {
+		if before[p] {
+			continue
+		}
+		kind := KindFile
+		if info, err := r.journal.base.Stat(p); err == nil && info.IsDir() {
+			kind = KindDir
+		}
+		r.journal.record(Entry{Kind: kind, Path: p})
+	}
+}
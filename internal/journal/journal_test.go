@@ -0,0 +1,216 @@
+package journal
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/runner"
+)
+
+// mustWrite creates path and its parents on fs, outside any journal.
+func mustWrite(t *testing.T, fs fsys.FS, path, data string) {
+	t.Helper()
+
+	if err := fs.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// creatingRunner is a Fake whose commands write files into fs, like
+// `go mod init` or `git init` would.
+type creatingRunner struct {
+	*runner.Fake
+	fs      fsys.FS
+	creates map[string][]string
+}
+
+func (r *creatingRunner) Run(cmd runner.Command) ([]byte, error) {
+	for _, p := range r.creates[cmd.String()] {
+		if err := r.fs.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			return nil, err
+		}
+		if err := r.fs.WriteFile(p, nil, 0o644); err != nil {
+			return nil, err
+		}
+	}
+	return r.Fake.Run(cmd)
+}
+
+func TestRollback(t *testing.T) {
+	base := fsys.NewMemory()
+	mustWrite(t, base, "/p/keep.txt", "mine")
+
+	j := New(base)
+	fs := j.FS()
+	if err := fs.MkdirAll("/p/a/b", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.WriteFile("/p/a/b/new.txt", []byte("new"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.WriteFile("/p/a/gen.txt", []byte("gen"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// a file the user adds while pcli runs keeps /p/a alive
+	mustWrite(t, base, "/p/a/user.txt", "user")
+
+	summary, err := j.Rollback()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"Removed /p/a/gen.txt",
+		"Removed /p/a/b/new.txt",
+		"Removed /p/a/b/",
+		"Kept /p/a (not empty)",
+	}
+	if !reflect.DeepEqual(summary, want) {
+		t.Errorf("summary = %q, want %q", summary, want)
+	}
+	if got, want := base.Paths(), []string{"/p", "/p/a", "/p/a/user.txt", "/p/keep.txt"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("left %q, want %q", got, want)
+	}
+	if j.HasChanges() || len(j.Entries()) != 0 {
+		t.Errorf("journal not reset: %v", j.Entries())
+	}
+}
+
+func TestRollbackRestoresOverwrite(t *testing.T) {
+	base := fsys.NewMemory()
+	if err := base.MkdirAll("/p", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := base.WriteFile("/p/.env", []byte("SECRET=1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	j := New(base)
+	for _, data := range []string{"A=1\n", "A=2\n"} {
+		if err := j.FS().WriteFile("/p/.env", []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := len(j.Entries()); n != 1 {
+		t.Errorf("journaled %d entries for two overwrites, want 1", n)
+	}
+
+	summary, err := j.Rollback()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Restored /p/.env"}; !reflect.DeepEqual(summary, want) {
+		t.Errorf("summary = %q, want %q", summary, want)
+	}
+
+	data, _ := base.ReadFile("/p/.env")
+	info, err := base.Stat("/p/.env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "SECRET=1\n" || info.Mode().Perm() != 0o600 {
+		t.Errorf("restored %q with mode %v, want the original content and 0600", data, info.Mode().Perm())
+	}
+}
+
+func TestCommandFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		before  []string
+		creates []string
+		want    []Entry
+	}{
+		{
+			name:    "new files",
+			creates: []string{"/p/go.mod", "/p/cmd/main.go"},
+			want: []Entry{
+				{Kind: KindCommand, Command: "tool", Dir: "/p"},
+				{Kind: KindDir, Path: "/p/cmd"},
+				{Kind: KindFile, Path: "/p/cmd/main.go"},
+				{Kind: KindFile, Path: "/p/go.mod"},
+			},
+		},
+		{
+			name:    "existing .git is skipped",
+			before:  []string{"/p/.git/HEAD"},
+			creates: []string{"/p/.git/objects/ab", "/p/README.md"},
+			want: []Entry{
+				{Kind: KindCommand, Command: "tool", Dir: "/p"},
+				{Kind: KindFile, Path: "/p/README.md"},
+			},
+		},
+		{
+			name:    "new .git is journaled",
+			creates: []string{"/p/.git/HEAD"},
+			want: []Entry{
+				{Kind: KindCommand, Command: "tool", Dir: "/p"},
+				{Kind: KindDir, Path: "/p/.git"},
+				{Kind: KindFile, Path: "/p/.git/HEAD"},
+			},
+		},
+		{
+			name:    "existing vendor is skipped",
+			before:  []string{"/p/vendor/modules.txt"},
+			creates: []string{"/p/vendor/x/y.go"},
+			want: []Entry{
+				{Kind: KindCommand, Command: "tool", Dir: "/p"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := fsys.NewMemory()
+			if err := base.MkdirAll("/p", 0o755); err != nil {
+				t.Fatal(err)
+			}
+			for _, p := range tt.before {
+				mustWrite(t, base, p, "")
+			}
+
+			j := New(base)
+			r := j.Runner(&creatingRunner{
+				Fake:    runner.NewFake(),
+				fs:      base,
+				creates: map[string][]string{"tool": tt.creates},
+			})
+			if _, err := r.Run(runner.Cmd("tool").In("/p")); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := j.Entries(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("entries = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommandFilesRollback(t *testing.T) {
+	base := fsys.NewMemory()
+	mustWrite(t, base, "/p/.git/HEAD", "")
+
+	j := New(base)
+	if err := j.FS().MkdirAll("/p/svc", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	r := j.Runner(&creatingRunner{
+		Fake:    runner.NewFake(),
+		fs:      base,
+		creates: map[string][]string{"git init": {"/p/svc/.git/HEAD", "/p/svc/.git/refs/heads/main"}},
+	})
+	if _, err := r.Run(runner.Cmd("git", "init").In("/p/svc")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := j.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := base.Paths(), []string{"/p", "/p/.git", "/p/.git/HEAD"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("left %q, want %q", got, want)
+	}
+}
@@ -4,10 +4,12 @@ import (
 	"bufio"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
+
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/runner"
 )
 
 type Language string
@@ -17,72 +19,35 @@ const (
 	// later: LanguageNode, LanguageTerraform, ...
 )
 
-func IsInstalled(lang Language) bool {
+func IsInstalled(r runner.Runner, lang Language) bool {
 	switch lang {
 	case LanguageGo:
-		_, err := exec.LookPath("go")
+		_, err := r.LookPath("go")
 		return err == nil
 	default:
 		return false
 	}
 }
 
-// InstallCommand returns an *exec.Cmd that attempts to install the language.
+// InstallCommand returns the command that attempts to install the language.
+// Run it with runner.Runner.Stream to get live output.
 //
 // Linux-only:
 //   - apt-get (Debian/Ubuntu)
 //   - dnf (Fedora/RHEL/...)
 //   - pacman (Arch/Manjaro/...)
 //
+// The distribution is read from /etc/os-release through fs, falling back to
+// looking up the package managers with r.
+//
 // Other OS: returns an error (no automatic install).
-func InstallCommand(lang Language) (*exec.Cmd, error) {
+func InstallCommand(r runner.Runner, fs fsys.FS, lang Language) (runner.Command, error) {
 	switch lang {
 	case LanguageGo:
-		return installGoCommand()
+		return installGoCommand(r, fs)
 	default:
-		return nil, fmt.Errorf("no installer defined for language: %s", lang)
-	}
-}
-
-// RunWithOutput launches the command, reads stdout and stderr line-by-line,
-// and pushes each line into the provided channel.
-//
-// Caller is responsible for closing the channel AFTER this returns
-// (or in a wrapper).
-func RunWithOutput(cmd *exec.Cmd, ch chan<- string) error {
-	stdout, err := cmd.StdoutPipe()
-	if err != nil {
-		return fmt.Errorf("failed to get stdout pipe: %w", err)
-	}
-
-	stderr, err := cmd.StderrPipe()
-	if err != nil {
-		return fmt.Errorf("failed to get stderr pipe: %w", err)
-	}
-
-	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("failed to start command: %w", err)
-	}
-
-	// Read stderr in a goroutine
-	go func() {
-		scanner := bufio.NewScanner(stderr)
-		for scanner.Scan() {
-			ch <- scanner.Text()
-		}
-	}()
-
-	// Read stdout in current goroutine
-	stdoutScanner := bufio.NewScanner(stdout)
-	for stdoutScanner.Scan() {
-		ch <- stdoutScanner.Text()
+		return runner.Command{}, fmt.Errorf("no installer defined for language: %s", lang)
 	}
-
-	if err := cmd.Wait(); err != nil {
-		return err
-	}
-
-	return nil
 }
 
 // ExpandPathEnv expands $HOME, $USER, etc. in path-like values,
@@ -101,29 +66,32 @@ func ExpandPathEnv(s string) string {
 
 // ------------ Linux-only installer helpers ------------
 
-func installGoCommand() (*exec.Cmd, error) {
+func installGoCommand(r runner.Runner, fs fsys.FS) (runner.Command, error) {
 	if runtime.GOOS != "linux" {
-		return nil, fmt.Errorf("automatic Go installation is only supported on Linux; please install Go manually")
+		return runner.Command{}, fmt.Errorf("automatic Go installation is only supported on Linux; please install Go manually")
 	}
 
-	pm := detectLinuxPackageManager()
+	pm := detectLinuxPackageManager(r, fs)
 	switch pm {
 	case "apt":
 		// Debian / Ubuntu
-		return exec.Command("sh", "-c", "sudo apt-get update && sudo apt-get install -y golang-go"), nil
+		return runner.Cmd("sh", "-c", "sudo apt-get update && sudo apt-get install -y golang-go"), nil
 	case "dnf":
 		// Fedora / RHEL / CentOS
-		return exec.Command("sh", "-c", "sudo dnf install -y golang"), nil
+		return runner.Cmd("sh", "-c", "sudo dnf install -y golang"), nil
 	case "pacman":
 		// Arch / Manjaro
-		return exec.Command("sh", "-c", "sudo pacman -Sy --noconfirm go"), nil
+		return runner.Cmd("sh", "-c", "sudo pacman -Sy --noconfirm go"), nil
 	default:
-		return nil, fmt.Errorf("unsupported Linux distro for automatic Go install; please install Go manually")
+		return runner.Command{}, fmt.Errorf("unsupported Linux distro for automatic Go install; please install Go manually")
 	}
 }
 
-func detectLinuxPackageManager() string {
-	data, err := os.ReadFile("/etc/os-release")
+// osReleasePath identifies the Linux distribution.
+const osReleasePath = "/etc/os-release"
+
+func detectLinuxPackageManager(r runner.Runner, fs fsys.FS) string {
+	data, err := fs.ReadFile(osReleasePath)
 	if err == nil {
 		id := parseOsReleaseID(string(data))
 		switch id {
@@ -137,13 +105,13 @@ func detectLinuxPackageManager() string {
 	}
 
 	// Fallback: check common binaries
-	if existsInPath("apt-get") {
+	if existsInPath(r, "apt-get") {
 		return "apt"
 	}
-	if existsInPath("dnf") {
+	if existsInPath(r, "dnf") {
 		return "dnf"
 	}
-	if existsInPath("pacman") {
+	if existsInPath(r, "pacman") {
 		return "pacman"
 	}
 
@@ -164,7 +132,7 @@ func parseOsReleaseID(content string) string {
 	return ""
 }
 
-func existsInPath(bin string) bool {
-	_, err := exec.LookPath(bin)
+func existsInPath(r runner.Runner, bin string) bool {
+	_, err := r.LookPath(bin)
 	return err == nil
 }
@@ -0,0 +1,72 @@
+package langenv
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/runner"
+)
+
+func TestIsInstalled(t *testing.T) {
+	r := runner.NewFake()
+	if IsInstalled(r, LanguageGo) {
+		t.Error("IsInstalled(go) without go in PATH")
+	}
+	r.WithPath("go", "/usr/bin/go")
+	if !IsInstalled(r, LanguageGo) {
+		t.Error("IsInstalled(go) with go in PATH = false")
+	}
+}
+
+func TestInstallCommand(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("automatic installs are Linux-only")
+	}
+
+	tests := []struct {
+		name      string
+		osRelease string
+		paths     []string
+		want      string
+	}{
+		{"ubuntu", "NAME=\"Ubuntu\"\nID=ubuntu\n", nil, "sh -c sudo apt-get update && sudo apt-get install -y golang-go"},
+		{"quoted id", "ID=\"fedora\"\n", nil, "sh -c sudo dnf install -y golang"},
+		{"arch", "ID=arch\n", nil, "sh -c sudo pacman -Sy --noconfirm go"},
+		{"unknown id falls back to PATH", "ID=gentoo\n", []string{"pacman"}, "sh -c sudo pacman -Sy --noconfirm go"},
+		{"no os-release", "", []string{"dnf"}, "sh -c sudo dnf install -y golang"},
+		{"unsupported", "ID=gentoo\n", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := fsys.NewMemory()
+			if tt.osRelease != "" {
+				if err := fs.MkdirAll("/etc", 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := fs.WriteFile(osReleasePath, []byte(tt.osRelease), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			r := runner.NewFake()
+			for _, p := range tt.paths {
+				r.WithPath(p, "/usr/bin/"+p)
+			}
+
+			cmd, err := InstallCommand(r, fs, LanguageGo)
+			if tt.want == "" {
+				if err == nil {
+					t.Errorf("InstallCommand = %s, want an error", cmd)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := cmd.String(); got != tt.want {
+				t.Errorf("InstallCommand = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package nav
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// PushMsg asks the navigator to show Model on top of the current screen.
+// ID identifies the screen for resuming it; Title labels it in the
+// breadcrumb.
+type PushMsg struct {
+	ID    string
+	Title string
+	Model tea.Model
+}
+
+// Push returns the command that pushes m, labelled title in the breadcrumb.
+// Pushing the id of the screen last left with Back resumes that screen
+// instead; an empty id is never resumed.
+func Push(id, title string, m tea.Model) tea.Cmd {
+	return func() tea.Msg {
+		return PushMsg{ID: id, Title: title, Model: m}
+	}
+}
+
+// BackMsg asks the navigator to return to the previous screen, in the
+// state it was left in.
+type BackMsg struct{}
+
+// Back is the tea.Cmd a screen returns when esc leaves its first step.
+func Back() tea.Msg {
+	return BackMsg{}
+}
+
+// Backer is implemented by screens that hold several steps of their own,
+// like the post-create chain. Back moves one of them back and reports
+// false when there is nothing left to go back to.
+type Backer interface {
+	Back() (tea.Model, bool)
+}
+
+// Crumbs is implemented by screens that add parts to the breadcrumb.
+type Crumbs interface {
+	Crumbs() []string
+}
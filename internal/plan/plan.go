@@ -0,0 +1,226 @@
+package plan
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type Kind int
+
+const (
+	KindDir Kind = iota
+	KindFile
+	KindCommand
+)
+
+// Op is a single recorded side effect.
+type Op struct {
+	Kind Kind
+
+	// Path is set for KindDir and KindFile.
+	Path string
+	Size int
+	// Exists is true when a KindFile op targets a file that is already on disk.
+	Exists bool
+
+	// Command and Dir are set for KindCommand.
+	Command string
+	Dir     string
+}
+
+// Plan collects the directories, files and commands a run would create.
+// It is safe for concurrent use.
+type Plan struct {
+	mu  sync.Mutex
+	ops []Op
+}
+
+func New() *Plan {
+	return &Plan{}
+}
+
+func (p *Plan) AddDir(path string) {
+	p.add(Op{Kind: KindDir, Path: filepath.Clean(path)})
+}
+
+func (p *Plan) AddFile(path string, size int, exists bool) {
+	p.add(Op{Kind: KindFile, Path: filepath.Clean(path), Size: size, Exists: exists})
+}
+
+func (p *Plan) AddCommand(command, dir string) {
+	p.add(Op{Kind: KindCommand, Command: command, Dir: dir})
+}
+
+func (p *Plan) add(op Op) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ops = append(p.ops, op)
+}
+
+// Ops returns a copy of every recorded op, in order.
+func (p *Plan) Ops() []Op {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	out := make([]Op, len(p.ops))
+	copy(out, p.ops)
+	return out
+}
+
+func (p *Plan) Empty() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.ops) == 0
+}
+
+// partialNote ends a rendered plan that contains commands: they are only
+// recorded, so whatever they would write is neither shown nor seen by the
+// steps that run after them.
+const partialNote = "This preview is partial: commands are not run, so the files they create " +
+	"(such as go.mod from go mod init) are not shown, and later steps that read them may do more in a real run.\n"
+
+// Render returns the recorded ops as a file tree followed by the command list.
+func (p *Plan) Render() string {
+	ops := p.Ops()
+	if len(ops) == 0 {
+		return "Nothing to do.\n"
+	}
+
+	var b strings.Builder
+
+	paths := map[string]Op{}
+	var commands []Op
+	for _, op := range ops {
+		switch op.Kind {
+		case KindDir, KindFile:
+			if prev, ok := paths[op.Path]; ok && prev.Kind == KindFile && op.Kind == KindFile {
+				op.Exists = op.Exists || prev.Exists
+			}
+			paths[op.Path] = op
+		case KindCommand:
+			commands = append(commands, op)
+		}
+	}
+
+	if len(paths) > 0 {
+		root := commonRoot(paths)
+		b.WriteString(root + "/\n")
+		writeTree(&b, root, paths)
+	}
+
+	if len(commands) > 0 {
+		if len(paths) > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("Commands:\n")
+		for _, c := range commands {
+			if c.Dir != "" {
+				fmt.Fprintf(&b, "  $ %s   (in %s)\n", c.Command, c.Dir)
+			} else {
+				fmt.Fprintf(&b, "  $ %s\n", c.Command)
+			}
+		}
+		b.WriteString("\n" + partialNote)
+	}
+
+	return b.String()
+}
+
+// commonRoot returns the deepest directory containing every path.
+func commonRoot(paths map[string]Op) string {
+	var root string
+	first := true
+	for path, op := range paths {
+		dir := filepath.Dir(path)
+		if op.Kind == KindDir {
+			dir = path
+		}
+		if first {
+			root = dir
+			first = false
+			continue
+		}
+		for !isWithin(root, dir) {
+			parent := filepath.Dir(root)
+			if parent == root {
+				break
+			}
+			root = parent
+		}
+	}
+	return root
+}
+
+func isWithin(root, path string) bool {
+	if root == path {
+		return true
+	}
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+type node struct {
+	name     string
+	op       *Op
+	children map[string]*node
+}
+
+func writeTree(b *strings.Builder, root string, paths map[string]Op) {
+	top := &node{children: map[string]*node{}}
+	for path, op := range paths {
+		if path == root {
+			continue
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			continue
+		}
+		cur := top
+		for _, part := range strings.Split(rel, string(filepath.Separator)) {
+			next, ok := cur.children[part]
+			if !ok {
+				next = &node{name: part, children: map[string]*node{}}
+				cur.children[part] = next
+			}
+			cur = next
+		}
+		op := op
+		cur.op = &op
+	}
+	writeChildren(b, top, "")
+}
+
+func writeChildren(b *strings.Builder, n *node, indent string) {
+	names := make([]string, 0, len(n.children))
+	for name := range n.children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for i, name := range names {
+		child := n.children[name]
+		branch, nextIndent := "├── ", indent+"│   "
+		if i == len(names)-1 {
+			branch, nextIndent = "└── ", indent+"    "
+		}
+
+		label := name
+		isDir := len(child.children) > 0 || (child.op != nil && child.op.Kind == KindDir)
+		if isDir {
+			label += "/"
+		}
+		if child.op != nil && child.op.Kind == KindFile {
+			if child.op.Exists {
+				label += fmt.Sprintf(" (overwrite, %d bytes)", child.op.Size)
+			} else {
+				label += fmt.Sprintf(" (%d bytes)", child.op.Size)
+			}
+		}
+
+		b.WriteString(indent + branch + label + "\n")
+		writeChildren(b, child, nextIndent)
+	}
+}
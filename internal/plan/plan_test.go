@@ -0,0 +1,39 @@
+package plan
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name    string
+		ops     func(p *Plan)
+		want    string
+		partial bool
+	}{
+		{"empty", func(p *Plan) {}, "Nothing to do.\n", false},
+		{"files only", func(p *Plan) {
+			p.AddDir("/p/svc")
+			p.AddFile("/p/svc/README.md", 10, false)
+		}, "/p/svc/\n└── README.md", false},
+		{"commands", func(p *Plan) {
+			p.AddDir("/p/svc")
+			p.AddCommand("go mod init example.com/svc", "/p/svc")
+		}, "Commands:\n  $ go mod init example.com/svc   (in /p/svc)\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New()
+			tt.ops(p)
+			got := p.Render()
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("Render() = %q, want it to contain %q", got, tt.want)
+			}
+			if partial := strings.HasSuffix(got, partialNote); partial != tt.partial {
+				t.Errorf("Render() marked partial = %v, want %v:\n%s", partial, tt.partial, got)
+			}
+		})
+	}
+}
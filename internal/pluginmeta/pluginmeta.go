@@ -0,0 +1,159 @@
+package pluginmeta
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// Metadata describes a plugin beyond its ID and name.
+type Metadata struct {
+	Version string   `json:"version,omitempty"`
+	Author  string   `json:"author,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+
+	// Category groups project types in the type chooser, e.g. language,
+	// framework or infra; empty means other.
+	Category string `json:"category,omitempty"`
+
+	// ProjectTypes lists the project types a post-create plugin supports;
+	// empty means every type.
+	ProjectTypes []string `json:"project_types,omitempty"`
+	// RequiredTools are executables the plugin needs on PATH.
+	RequiredTools []string `json:"required_tools,omitempty"`
+
+	// After and Before are ordering hints: plugin IDs this plugin should
+	// run after or before when both apply.
+	After  []string `json:"after,omitempty"`
+	Before []string `json:"before,omitempty"`
+	// Conflicts are plugin IDs that cannot be registered alongside this one.
+	Conflicts []string `json:"conflicts,omitempty"`
+}
+
+// Categories are the well-known categories, in the order the type chooser
+// shows them; other categories follow alphabetically, then uncategorized
+// plugins.
+var Categories = []string{"language", "framework", "infra"}
+
+// Supports reports whether the plugin applies to projectType.
+func (m Metadata) Supports(projectType string) bool {
+	if len(m.ProjectTypes) == 0 {
+		return true
+	}
+	for _, t := range m.ProjectTypes {
+		if t == projectType {
+			return true
+		}
+	}
+	return false
+}
+
+// ConflictsWith reports whether m declares a conflict with id.
+func (m Metadata) ConflictsWith(id string) bool {
+	for _, c := range m.Conflicts {
+		if c == id {
+			return true
+		}
+	}
+	return false
+}
+
+var idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]*$`)
+
+// Validate checks a plugin's ID and metadata on their own.
+func Validate(id string, m Metadata) error {
+	if !idPattern.MatchString(id) {
+		return fmt.Errorf("invalid plugin id %q: use lowercase letters, digits, '.', '_' and '-'", id)
+	}
+	for _, list := range [][]string{m.After, m.Before, m.Conflicts} {
+		for _, other := range list {
+			if other == id {
+				return fmt.Errorf("plugin %q: metadata refers to itself", id)
+			}
+		}
+	}
+	return nil
+}
+
+// Node is what Order needs to know about a plugin.
+type Node struct {
+	ID   string
+	Meta Metadata
+}
+
+// Order returns the indexes of nodes sorted so that every After/Before hint
+// between present nodes is honoured, keeping the original order otherwise.
+// Hints that form a cycle are ignored for the nodes involved.
+func Order(nodes []Node) []int {
+	index := map[string]int{}
+	for i, n := range nodes {
+		index[n.ID] = i
+	}
+
+	// edges[a] holds the nodes that must come after a
+	edges := make([][]int, len(nodes))
+	indegree := make([]int, len(nodes))
+	addEdge := func(from, to int) {
+		edges[from] = append(edges[from], to)
+		indegree[to]++
+	}
+	for i, n := range nodes {
+		for _, a := range n.Meta.After {
+			if j, ok := index[a]; ok {
+				addEdge(j, i)
+			}
+		}
+		for _, b := range n.Meta.Before {
+			if j, ok := index[b]; ok {
+				addEdge(i, j)
+			}
+		}
+	}
+
+	done := make([]bool, len(nodes))
+	out := make([]int, 0, len(nodes))
+	for len(out) < len(nodes) {
+		var ready []int
+		for i := range nodes {
+			if !done[i] && indegree[i] == 0 {
+				ready = append(ready, i)
+			}
+		}
+		if len(ready) == 0 {
+			// cycle: release the earliest remaining node
+			for i := range nodes {
+				if !done[i] {
+					ready = []int{i}
+					break
+				}
+			}
+		}
+		sort.Ints(ready)
+
+		i := ready[0]
+		done[i] = true
+		out = append(out, i)
+		for _, j := range edges[i] {
+			indegree[j]--
+		}
+	}
+	return out
+}
+
+// CheckRegistration validates a new plugin against the ones already
+// registered in the same registry: its ID must be unique and no conflict may
+// be declared in either direction.
+func CheckRegistration(n Node, registered []Node) error {
+	if err := Validate(n.ID, n.Meta); err != nil {
+		return err
+	}
+	for _, r := range registered {
+		if r.ID == n.ID {
+			return fmt.Errorf("plugin %q is already registered", n.ID)
+		}
+		if n.Meta.ConflictsWith(r.ID) || r.Meta.ConflictsWith(n.ID) {
+			return fmt.Errorf("plugin %q conflicts with registered plugin %q", n.ID, r.ID)
+		}
+	}
+	return nil
+}
@@ -1,11 +1,50 @@
 package plugins
 
 import (
+	"errors"
+
+	"github.com/ezeqielle/pcli/internal/extplugin"
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/postplugin"
+	"github.com/ezeqielle/pcli/internal/postplugin/devenv"
+	"github.com/ezeqielle/pcli/internal/postplugin/docker"
+	"github.com/ezeqielle/pcli/internal/postplugin/env"
+	global "github.com/ezeqielle/pcli/internal/postplugin/global"
+	"github.com/ezeqielle/pcli/internal/postplugin/golayout"
+	"github.com/ezeqielle/pcli/internal/postplugin/goquality"
+	"github.com/ezeqielle/pcli/internal/postplugin/license"
 	"github.com/ezeqielle/pcli/internal/projecttype"
 	goproject "github.com/ezeqielle/pcli/internal/projecttype/go"
+	"github.com/ezeqielle/pcli/internal/runner"
+	"github.com/ezeqielle/pcli/internal/settings"
 )
 
-func RegisterAll() {
-	projecttype.Register(goproject.New())
+// RegisterAll registers the built-in plugins, then every external
+// pcli-plugin-* executable found, and applies cfg to the registries.
+// Plugins that fail validation or fail to load are skipped and reported in
+// the returned error. query runs the read-only commands plugins use to
+// pick their defaults, such as git config; it is neither journaled nor
+// dry-run.
+func RegisterAll(r, query runner.Runner, fs fsys.FS, cfg *settings.Config) error {
+	var errs []error
+
+	projecttype.Configure(cfg.ProjectTypes)
+	postplugin.Configure(cfg.PostCreate)
+
+	errs = append(errs, projecttype.Register(goproject.New(r, fs)))
 	// later: register typescript, terraform, ...
+
+	errs = append(errs,
+		postplugin.Register(global.New(r, fs, cfg.Items)),
+		postplugin.Register(env.New(r, fs, cfg.Items)),
+		postplugin.Register(golayout.New(r, fs, cfg.Items)),
+		postplugin.Register(goquality.New(r, fs, cfg.Items)),
+		postplugin.Register(docker.New(r, fs, cfg.Items)),
+		postplugin.Register(devenv.New(r, fs, cfg.Items)),
+		postplugin.Register(license.New(r, query, fs, cfg.Items)),
+	)
+
+	errs = append(errs, extplugin.RegisterDiscovered(r, fs))
+
+	return errors.Join(errs...)
 }
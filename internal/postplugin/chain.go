@@ -0,0 +1,166 @@
+package postplugin
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// DoneMsg tells the Chain that the current post-create wizard has finished.
+type DoneMsg struct{}
+
+// Finish is the tea.Cmd a post-create wizard returns once it is done.
+func Finish() tea.Msg {
+	return DoneMsg{}
+}
+
+// AbortMsg tells the Chain that the current post-create wizard rolled the
+// run back: the project is gone, so no further plugin runs.
+type AbortMsg struct{}
+
+// Abort is the tea.Cmd a post-create wizard returns instead of Finish once
+// it has rolled the run back.
+func Abort() tea.Msg {
+	return AbortMsg{}
+}
+
+// startMsg makes a Chain start its first wizard.
+type startMsg struct{}
+
+// Chain runs post-create plugin wizards one after the other on the same
+// project and quits after the last one. Wizards that were started are kept,
+// so going back with nav.Back returns to the previous plugin as it was left.
+type Chain struct {
+	projectPath string
+	projectType string
+
+	plugins []Plugin
+	index   int
+	current tea.Model
+	started []tea.Model
+
+	// size is the last terminal size, passed on to wizards as they start
+	size *tea.WindowSizeMsg
+}
+
+// NewChain runs plugins against the project in order. No wizard is created
+// before the chain is shown: Init asks for the first one.
+func NewChain(projectPath, projectType string, plugins []Plugin) Chain {
+	return Chain{
+		projectPath: projectPath,
+		projectType: projectType,
+		plugins:     plugins,
+	}
+}
+
+// NewChainFor runs every registered plugin supporting projectType.
+func NewChainFor(projectPath, projectType string) Chain {
+	return NewChain(projectPath, projectType, For(projectType))
+}
+
+// Empty reports whether the chain has no plugin to run.
+func (c Chain) Empty() bool {
+	return len(c.plugins) == 0
+}
+
+func (c Chain) Init() tea.Cmd {
+	if len(c.plugins) == 0 {
+		return tea.Quit
+	}
+	return func() tea.Msg { return startMsg{} }
+}
+
+func (c *Chain) start(i int) tea.Cmd {
+	if c.current != nil {
+		c.keep()
+	}
+	c.index = i
+	if i < len(c.started) {
+		// came back to a plugin that was already started
+		c.current = c.started[i]
+		return nil
+	}
+	c.current = c.plugins[i].NewWizard(c.projectPath, c.projectType)
+	cmd := c.current.Init()
+	if c.size != nil {
+		var sizeCmd tea.Cmd
+		c.current, sizeCmd = c.current.Update(*c.size)
+		cmd = tea.Batch(cmd, sizeCmd)
+	}
+	return cmd
+}
+
+// keep records the current wizard's state under its index.
+func (c *Chain) keep() {
+	started := append([]tea.Model(nil), c.started...)
+	if c.index < len(started) {
+		started[c.index] = c.current
+	} else {
+		started = append(started, c.current)
+	}
+	c.started = started
+}
+
+// Back returns to the previous plugin's wizard, implementing nav.Backer.
+func (c Chain) Back() (tea.Model, bool) {
+	if c.index == 0 {
+		return c, false
+	}
+	c.keep()
+	c.index--
+	c.current = c.started[c.index]
+	return c, true
+}
+
+// Crumbs names the running plugin in the navigator's breadcrumb.
+func (c Chain) Crumbs() []string {
+	if c.current == nil {
+		return nil
+	}
+	name := c.plugins[c.index].DisplayName()
+	if len(c.plugins) > 1 {
+		name += fmt.Sprintf(" (%d/%d)", c.index+1, len(c.plugins))
+	}
+	return []string{name}
+}
+
+func (c Chain) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		c.size = &size
+	}
+
+	if _, ok := msg.(startMsg); ok {
+		if c.current != nil || len(c.plugins) == 0 {
+			return c, nil
+		}
+		cmd := c.start(0)
+		return c, cmd
+	}
+
+	if c.current == nil {
+		return c, nil
+	}
+
+	if _, ok := msg.(AbortMsg); ok {
+		return c, tea.Quit
+	}
+
+	if _, ok := msg.(DoneMsg); ok {
+		if c.index+1 >= len(c.plugins) {
+			return c, tea.Quit
+		}
+		cmd := c.start(c.index + 1)
+		return c, cmd
+	}
+
+	var cmd tea.Cmd
+	c.current, cmd = c.current.Update(msg)
+	return c, cmd
+}
+
+func (c Chain) View() string {
+	if c.current == nil {
+		return ""
+	}
+	return c.current.View()
+}
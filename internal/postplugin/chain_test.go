@@ -0,0 +1,121 @@
+package postplugin
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ezeqielle/pcli/internal/pluginmeta"
+)
+
+// countingPlugin counts the wizards it creates; each wizard sends the
+// message of its cmd on any key.
+type countingPlugin struct {
+	id      string
+	wizards int
+	cmd     tea.Cmd
+}
+
+func (p *countingPlugin) ID() string                    { return p.id }
+func (p *countingPlugin) DisplayName() string           { return p.id }
+func (p *countingPlugin) Metadata() pluginmeta.Metadata { return pluginmeta.Metadata{} }
+
+func (p *countingPlugin) NewWizard(projectPath, projectType string) tea.Model {
+	p.wizards++
+	return keyModel{id: p.id, cmd: p.cmd}
+}
+
+type keyModel struct {
+	id  string
+	cmd tea.Cmd
+}
+
+func (m keyModel) Init() tea.Cmd { return nil }
+func (m keyModel) View() string  { return m.id }
+
+func (m keyModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(tea.KeyMsg); ok {
+		return m, m.cmd
+	}
+	return m, nil
+}
+
+// press sends a key to c and feeds the resulting message back, like the
+// tea runtime would.
+func press(t *testing.T, c tea.Model) (tea.Model, tea.Msg) {
+	t.Helper()
+
+	c, cmd := c.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		return c, nil
+	}
+	msg := cmd()
+	c, _ = c.Update(msg)
+	return c, msg
+}
+
+func TestChain(t *testing.T) {
+	a := &countingPlugin{id: "a", cmd: Finish}
+	b := &countingPlugin{id: "b", cmd: Finish}
+
+	c := NewChain("/p", "go", []Plugin{a, b})
+	if a.wizards != 0 {
+		t.Fatalf("NewChain created %d wizards, want none", a.wizards)
+	}
+
+	var m tea.Model = c
+	m, _ = m.Update(c.Init()())
+	if a.wizards != 1 || m.View() != "a" {
+		t.Fatalf("after Init: view %q, %d wizards of a", m.View(), a.wizards)
+	}
+	m, _ = m.Update(c.Init()())
+	if a.wizards != 1 {
+		t.Errorf("a second start created another wizard")
+	}
+
+	m, _ = press(t, m)
+	if b.wizards != 1 || m.View() != "b" {
+		t.Fatalf("after a finished: view %q, %d wizards of b", m.View(), b.wizards)
+	}
+
+	m, back := m.(Chain).Back()
+	if !back || m.View() != "a" || a.wizards != 1 {
+		t.Errorf("Back: view %q, moved %v, %d wizards of a", m.View(), back, a.wizards)
+	}
+
+	_, cmd := NewChain("/p", "go", nil).Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd != nil {
+		t.Error("an empty chain reacted to a key")
+	}
+	if msg := NewChain("/p", "go", nil).Init()(); msg != tea.Quit() {
+		t.Errorf("empty chain Init = %v, want quit", msg)
+	}
+}
+
+func TestChainQuitsAfterLast(t *testing.T) {
+	a := &countingPlugin{id: "a", cmd: Finish}
+	c := NewChain("/p", "go", []Plugin{a})
+
+	m, _ := c.Update(c.Init()())
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	_, cmd = m.Update(cmd())
+	if cmd == nil || cmd() != tea.Quit() {
+		t.Error("the chain did not quit after its last plugin")
+	}
+}
+
+func TestChainAbort(t *testing.T) {
+	a := &countingPlugin{id: "a", cmd: Abort}
+	b := &countingPlugin{id: "b", cmd: Finish}
+	c := NewChain("/p", "go", []Plugin{a, b})
+
+	m, _ := c.Update(c.Init()())
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	_, cmd = m.Update(cmd())
+	if cmd == nil || cmd() != tea.Quit() {
+		t.Error("the chain did not quit after a rollback")
+	}
+	if b.wizards != 0 {
+		t.Error("the chain started the next plugin after a rollback")
+	}
+}
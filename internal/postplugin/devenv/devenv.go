@@ -0,0 +1,219 @@
+package devenv
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ezeqielle/pcli/internal/conflict"
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/gomod"
+	"github.com/ezeqielle/pcli/internal/journal"
+	"github.com/ezeqielle/pcli/internal/pluginmeta"
+	"github.com/ezeqielle/pcli/internal/postplugin"
+	"github.com/ezeqielle/pcli/internal/question"
+	"github.com/ezeqielle/pcli/internal/runner"
+	"github.com/ezeqielle/pcli/internal/settings"
+	"github.com/ezeqielle/pcli/internal/version"
+	"github.com/ezeqielle/pcli/internal/wizard"
+)
+
+// DevEnvPlugin implements a post-create plugin that generates reproducible
+// development environments: a dev container, a Nix flake or shell, or a
+// mise.toml, all pinning the toolchain version the project declares.
+type DevEnvPlugin struct {
+	runner runner.Runner
+	fs     fsys.FS
+	items  settings.Items
+}
+
+// New returns the plugin; items overrides the default state of its files.
+func New(r runner.Runner, fs fsys.FS, items settings.Items) *DevEnvPlugin {
+	return &DevEnvPlugin{runner: r, fs: fs, items: items}
+}
+
+func (p *DevEnvPlugin) ID() string {
+	return "devenv"
+}
+
+func (p *DevEnvPlugin) DisplayName() string {
+	return "Dev environment"
+}
+
+func (p *DevEnvPlugin) Metadata() pluginmeta.Metadata {
+	return pluginmeta.Metadata{
+		Version:      version.Version,
+		Author:       "pcli",
+		Tags:         []string{"devcontainer", "nix", "mise", "toolchain"},
+		ProjectTypes: []string{"go"},
+		After:        []string{"global", "go_layout", "docker"},
+	}
+}
+
+type item struct {
+	ID       string
+	Label    string
+	Selected bool
+	File     string
+}
+
+// state gives settings.Filter the item's ID and selection.
+func (it *item) state() (string, *bool) {
+	return it.ID, &it.Selected
+}
+
+// fileItems returns the files the plugin can generate.
+func fileItems() []item {
+	return []item{
+		{ID: "devenv_devcontainer", Label: "Dev container (.devcontainer/devcontainer.json)", Selected: true, File: ".devcontainer/devcontainer.json"},
+		{ID: "devenv_flake", Label: "Nix flake (flake.nix)", Selected: false, File: "flake.nix"},
+		{ID: "devenv_shell", Label: "Nix shell (shell.nix)", Selected: false, File: "shell.nix"},
+		{ID: "devenv_mise", Label: "mise (mise.toml)", Selected: false, File: "mise.toml"},
+	}
+}
+
+// Inspect reports which of the plugin's files already exist in projectPath.
+func (p *DevEnvPlugin) Inspect(projectPath, projectType string) []postplugin.ItemStatus {
+	var out []postplugin.ItemStatus
+	for _, it := range fileItems() {
+		out = append(out, postplugin.ItemStatus{
+			ID:      it.ID,
+			Label:   it.Label,
+			Path:    it.File,
+			Present: fsys.Exists(p.fs, filepath.Join(projectPath, filepath.FromSlash(it.File))),
+		})
+	}
+	return out
+}
+
+// Questions asks which environment files to generate. The help line shows
+// the toolchain version they will pin.
+func (p *DevEnvPlugin) Questions(projectPath, projectType string) ([]question.Question, error) {
+	var files []question.Option
+	defaultFiles := []string{}
+	for _, it := range settings.Filter(p.items, fileItems(), (*item).state) {
+		files = append(files, question.Option{Value: it.ID, Label: it.Label})
+		if it.Selected {
+			defaultFiles = append(defaultFiles, it.ID)
+		}
+	}
+
+	help := "No toolchain version found; the latest release is used."
+	if projectPath == "" {
+		help = "Pins the toolchain version found in the project, or the latest release."
+	} else if tc := p.toolchain(projectPath, projectType); tc.Version != "" {
+		help = "Pins " + projectType + " " + tc.Version + " from the project."
+	}
+
+	return []question.Question{
+		{
+			ID:      "files",
+			Type:    question.MultiSelect,
+			Label:   "Select development environments to generate",
+			Help:    help,
+			Default: defaultFiles,
+			Options: files,
+		},
+	}, nil
+}
+
+// toolchain returns the tools to pin for projectType, with the version the
+// project declares.
+func (p *DevEnvPlugin) toolchain(projectPath, projectType string) toolchain {
+	switch projectType {
+	case "go":
+		return p.goToolchain(projectPath)
+	}
+	return toolchain{Image: "mcr.microsoft.com/devcontainers/base:bookworm"}
+}
+
+// goToolchain reads the go directive of go.mod. The dev container image
+// and the nixpkgs attribute only exist per minor release; mise takes the
+// full version.
+func (p *DevEnvPlugin) goToolchain(projectPath string) toolchain {
+	tc := toolchain{
+		Image:      "mcr.microsoft.com/devcontainers/go:1",
+		Extensions: []string{"golang.go"},
+		Settings: map[string]any{
+			"go.toolsManagement.autoUpdate": true,
+			"go.lintTool":                   "golangci-lint",
+		},
+		PostCreate: "go mod download",
+		Nix:        []string{"go", "gopls", "gotools", "golangci-lint"},
+		Mise:       [][2]string{{"go", "latest"}, {"golangci-lint", "latest"}},
+	}
+
+	if mod, err := gomod.Read(p.fs, projectPath); err == nil && mod.GoVersion != "" {
+		minor := mod.MinorVersion()
+		tc.Version = mod.GoVersion
+		tc.Image = "mcr.microsoft.com/devcontainers/go:1-" + minor + "-bookworm"
+		tc.Nix[0] = "go_" + strings.ReplaceAll(minor, ".", "_")
+		tc.Mise[0][1] = mod.GoVersion
+	}
+
+	if fsys.Exists(p.fs, filepath.Join(projectPath, "Dockerfile")) {
+		tc.Extensions = append(tc.Extensions, "ms-azuretools.vscode-docker")
+	}
+	return tc
+}
+
+// Apply writes the selected files; existing files are kept.
+func (p *DevEnvPlugin) Apply(projectPath, projectType string, answers question.Answers) ([]string, error) {
+	selected := map[string]bool{}
+	for _, id := range answers.Strings("files") {
+		selected[id] = true
+	}
+
+	tc := p.toolchain(projectPath, projectType)
+
+	var summary []string
+	for _, it := range fileItems() {
+		if !selected[it.ID] {
+			continue
+		}
+
+		data, err := p.render(it.ID, filepath.Base(projectPath), tc)
+		if err != nil {
+			return summary, fmt.Errorf("failed to render %s: %w", it.File, err)
+		}
+
+		path := filepath.Join(projectPath, filepath.FromSlash(it.File))
+		if err := p.fs.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return summary, fmt.Errorf("failed to create %s: %w", filepath.Dir(it.File), err)
+		}
+		action, err := conflict.Write(p.fs, path, data, 0o644, conflict.Skip)
+		if err != nil {
+			return summary, fmt.Errorf("failed to write %s: %w", it.File, err)
+		}
+		summary = append(summary, it.File+" "+action)
+	}
+
+	if j := journal.Of(p.fs); j != nil {
+		j.AddPlugin("devenv")
+	}
+
+	if len(summary) == 0 {
+		summary = []string{"No files were selected."}
+	}
+	return summary, nil
+}
+
+func (p *DevEnvPlugin) render(id, name string, tc toolchain) ([]byte, error) {
+	switch id {
+	case "devenv_devcontainer":
+		return renderDevcontainer(name, tc)
+	case "devenv_flake":
+		return renderNix(flakeFile, name, tc)
+	case "devenv_shell":
+		return renderNix(shellFile, name, tc)
+	case "devenv_mise":
+		return render(miseFile, tc.Mise)
+	}
+	return nil, fmt.Errorf("unknown item %q", id)
+}
+
+func (p *DevEnvPlugin) NewWizard(projectPath, projectType string) tea.Model {
+	return wizard.NewPostCreate(p, p.fs, "Dev environment", projectPath, projectType)
+}
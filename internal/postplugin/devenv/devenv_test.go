@@ -0,0 +1,155 @@
+package devenv
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/question"
+	"github.com/ezeqielle/pcli/internal/runner"
+)
+
+var allFiles = []string{"devenv_devcontainer", "devenv_flake", "devenv_shell", "devenv_mise"}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name        string
+		projectType string
+		files       map[string]string
+		image       string
+		extensions  []string
+		nix         string
+		mise        string
+	}{
+		{
+			name:        "go with a go directive",
+			projectType: "go",
+			files:       map[string]string{"go.mod": "module example.com/api\n\ngo 1.24.2\n"},
+			image:       "mcr.microsoft.com/devcontainers/go:1-1.24-bookworm",
+			extensions:  []string{"golang.go"},
+			nix:         "[ go_1_24 gopls gotools golangci-lint ]",
+			mise:        "[tools]\ngo = \"1.24.2\"\ngolangci-lint = \"latest\"\n",
+		},
+		{
+			name:        "go without go.mod",
+			projectType: "go",
+			image:       "mcr.microsoft.com/devcontainers/go:1",
+			extensions:  []string{"golang.go"},
+			nix:         "[ go gopls gotools golangci-lint ]",
+			mise:        "[tools]\ngo = \"latest\"\ngolangci-lint = \"latest\"\n",
+		},
+		{
+			name:        "go with a Dockerfile",
+			projectType: "go",
+			files:       map[string]string{"go.mod": "module api\n\ngo 1.25\n", "Dockerfile": "FROM scratch\n"},
+			image:       "mcr.microsoft.com/devcontainers/go:1-1.25-bookworm",
+			extensions:  []string{"golang.go", "ms-azuretools.vscode-docker"},
+			nix:         "[ go_1_25 gopls gotools golangci-lint ]",
+			mise:        "[tools]\ngo = \"1.25\"\ngolangci-lint = \"latest\"\n",
+		},
+		{
+			name:        "other type",
+			projectType: "terraform",
+			image:       "mcr.microsoft.com/devcontainers/base:bookworm",
+			nix:         "[  ]",
+			mise:        "[tools]\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := fsys.NewMemory()
+			if err := fs.MkdirAll("/src/api", 0o755); err != nil {
+				t.Fatal(err)
+			}
+			for name, data := range tt.files {
+				if err := fs.WriteFile("/src/api/"+name, []byte(data), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			answers := question.Answers{"files": allFiles}
+			if _, err := New(runner.NewFake(), fs, nil).Apply("/src/api", tt.projectType, answers); err != nil {
+				t.Fatal(err)
+			}
+
+			data, _ := fs.ReadFile("/src/api/.devcontainer/devcontainer.json")
+			var dc devcontainer
+			if err := json.Unmarshal(data, &dc); err != nil {
+				t.Fatalf("devcontainer.json: %v\n%s", err, data)
+			}
+			if dc.Name != "api" || dc.Image != tt.image {
+				t.Errorf("devcontainer name %q, image %q, want api, %q", dc.Name, dc.Image, tt.image)
+			}
+			if !reflect.DeepEqual(dc.Customizations.VSCode.Extensions, tt.extensions) {
+				t.Errorf("extensions = %q, want %q", dc.Customizations.VSCode.Extensions, tt.extensions)
+			}
+
+			for _, file := range []string{"flake.nix", "shell.nix"} {
+				data, _ := fs.ReadFile("/src/api/" + file)
+				if want := "packages = with pkgs; " + tt.nix + ";"; !strings.Contains(string(data), want) {
+					t.Errorf("%s does not contain %q:\n%s", file, want, data)
+				}
+			}
+			if data, _ := fs.ReadFile("/src/api/flake.nix"); !strings.Contains(string(data), `description = "api development environment";`) {
+				t.Errorf("flake.nix has no description:\n%s", data)
+			}
+
+			if data, _ := fs.ReadFile("/src/api/mise.toml"); string(data) != tt.mise {
+				t.Errorf("mise.toml = %q, want %q", data, tt.mise)
+			}
+		})
+	}
+}
+
+func TestApplyKeepsExistingFiles(t *testing.T) {
+	fs := fsys.NewMemory()
+	if err := fs.MkdirAll("/p", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.WriteFile("/p/mise.toml", []byte("[tools]\nnode = \"22\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	summary, err := New(runner.NewFake(), fs, nil).Apply("/p", "go", question.Answers{"files": []string{"devenv_mise"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"mise.toml already exists (skipped)"}; !reflect.DeepEqual(summary, want) {
+		t.Errorf("summary = %q, want %q", summary, want)
+	}
+	if data, _ := fs.ReadFile("/p/mise.toml"); string(data) != "[tools]\nnode = \"22\"\n" {
+		t.Errorf("mise.toml was changed: %q", data)
+	}
+}
+
+func TestQuestionsHelp(t *testing.T) {
+	fs := fsys.NewMemory()
+	if err := fs.MkdirAll("/p", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.WriteFile("/p/go.mod", []byte("module p\n\ngo 1.24.2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		project string
+		want    string
+	}{
+		{"/p", "Pins go 1.24.2 from the project."},
+		{"/empty", "No toolchain version found; the latest release is used."},
+		{"", "Pins the toolchain version found in the project, or the latest release."},
+	}
+
+	for _, tt := range tests {
+		qs, err := New(runner.NewFake(), fs, nil).Questions(tt.project, "go")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(qs) != 1 || qs[0].Help != tt.want {
+			t.Errorf("Questions(%q) help = %q, want %q", tt.project, qs[0].Help, tt.want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package devenv
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"text/template"
+)
+
+// toolchain is what the environment files pin for a project type.
+type toolchain struct {
+	// Version is the toolchain version read from the project, e.g. 1.24.2;
+	// empty when it could not be detected.
+	Version string
+	// Image is the dev container base image.
+	Image string
+	// Extensions are the recommended VS Code extensions.
+	Extensions []string
+	// Settings are VS Code settings for the container.
+	Settings map[string]any
+	// PostCreate runs once the container is created.
+	PostCreate string
+	// Nix are the nixpkgs attributes of the dev shell.
+	Nix []string
+	// Mise are the mise tools and their versions.
+	Mise [][2]string
+}
+
+// devcontainer is the subset of devcontainer.json pcli writes.
+type devcontainer struct {
+	Name              string         `json:"name"`
+	Image             string         `json:"image"`
+	Customizations    customizations `json:"customizations"`
+	PostCreateCommand string         `json:"postCreateCommand,omitempty"`
+}
+
+type customizations struct {
+	VSCode vscode `json:"vscode"`
+}
+
+type vscode struct {
+	Extensions []string       `json:"extensions"`
+	Settings   map[string]any `json:"settings,omitempty"`
+}
+
+func renderDevcontainer(name string, tc toolchain) ([]byte, error) {
+	data, err := json.MarshalIndent(devcontainer{
+		Name:  name,
+		Image: tc.Image,
+		Customizations: customizations{VSCode: vscode{
+			Extensions: tc.Extensions,
+			Settings:   tc.Settings,
+		}},
+		PostCreateCommand: tc.PostCreate,
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// flakeFile is a flake with a dev shell for every default system.
+var flakeFile = template.Must(template.New("flake.nix").Parse(`{
+  description = "{{.Name}} development environment";
+
+  inputs = {
+    nixpkgs.url = "github:NixOS/nixpkgs/nixos-unstable";
+    flake-utils.url = "github:numtide/flake-utils";
+  };
+
+  outputs = { self, nixpkgs, flake-utils }:
+    flake-utils.lib.eachDefaultSystem (system:
+      let
+        pkgs = nixpkgs.legacyPackages.${system};
+      in
+      {
+        devShells.default = pkgs.mkShell {
+          packages = with pkgs; [ {{.Packages}} ];
+        };
+      });
+}
+`))
+
+// shellFile is the same dev shell for nix-shell without flakes.
+var shellFile = template.Must(template.New("shell.nix").Parse(`{ pkgs ? import <nixpkgs> { } }:
+
+pkgs.mkShell {
+  packages = with pkgs; [ {{.Packages}} ];
+}
+`))
+
+// miseFile pins the tools for mise.
+var miseFile = template.Must(template.New("mise.toml").Parse(`[tools]
+{{- range .}}
+{{index . 0}} = "{{index . 1}}"
+{{- end}}
+`))
+
+func renderNix(t *template.Template, name string, tc toolchain) ([]byte, error) {
+	return render(t, struct {
+		Name     string
+		Packages string
+	}{name, strings.Join(tc.Nix, " ")})
+}
+
+func render(t *template.Template, data any) ([]byte, error) {
+	var b bytes.Buffer
+	if err := t.Execute(&b, data); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
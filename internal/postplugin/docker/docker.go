@@ -0,0 +1,284 @@
+package docker
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ezeqielle/pcli/internal/conflict"
+	"github.com/ezeqielle/pcli/internal/envfile"
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/gomod"
+	"github.com/ezeqielle/pcli/internal/journal"
+	"github.com/ezeqielle/pcli/internal/pluginmeta"
+	"github.com/ezeqielle/pcli/internal/postplugin"
+	"github.com/ezeqielle/pcli/internal/question"
+	"github.com/ezeqielle/pcli/internal/runner"
+	"github.com/ezeqielle/pcli/internal/settings"
+	"github.com/ezeqielle/pcli/internal/version"
+	"github.com/ezeqielle/pcli/internal/wizard"
+)
+
+// DockerPlugin implements a post-create plugin that containerises the
+// project: a multi-stage Dockerfile, a .dockerignore and a compose.yaml
+// with companion services whose settings go into .env.
+type DockerPlugin struct {
+	runner runner.Runner
+	fs     fsys.FS
+	items  settings.Items
+}
+
+// New returns the plugin; items overrides the default state of its files
+// and services.
+func New(r runner.Runner, fs fsys.FS, items settings.Items) *DockerPlugin {
+	return &DockerPlugin{runner: r, fs: fs, items: items}
+}
+
+func (p *DockerPlugin) ID() string {
+	return "docker"
+}
+
+func (p *DockerPlugin) DisplayName() string {
+	return "Docker and compose"
+}
+
+func (p *DockerPlugin) Metadata() pluginmeta.Metadata {
+	return pluginmeta.Metadata{
+		Version:      version.Version,
+		Author:       "pcli",
+		Tags:         []string{"docker", "container", "compose"},
+		ProjectTypes: []string{"go"},
+		After:        []string{"global", "env", "go_layout"},
+	}
+}
+
+type item struct {
+	ID       string
+	Label    string
+	Selected bool
+	File     string
+}
+
+// state gives settings.Filter the item's ID and selection.
+func (it *item) state() (string, *bool) {
+	return it.ID, &it.Selected
+}
+
+// fileItems returns the files the plugin can generate.
+func fileItems() []item {
+	return []item{
+		{ID: "docker_dockerfile", Label: "Dockerfile (multi-stage)", Selected: true, File: "Dockerfile"},
+		{ID: "docker_dockerignore", Label: ".dockerignore", Selected: true, File: ".dockerignore"},
+		{ID: "docker_compose", Label: "compose.yaml", Selected: false, File: "compose.yaml"},
+	}
+}
+
+// Inspect reports which of the plugin's files already exist in projectPath.
+func (p *DockerPlugin) Inspect(projectPath, projectType string) []postplugin.ItemStatus {
+	var out []postplugin.ItemStatus
+	for _, it := range fileItems() {
+		out = append(out, postplugin.ItemStatus{
+			ID:      it.ID,
+			Label:   it.Label,
+			Path:    it.File,
+			Present: fsys.Exists(p.fs, filepath.Join(projectPath, it.File)),
+		})
+	}
+	return out
+}
+
+// Questions asks which files to generate, the Dockerfile's final stage and
+// the companion services of compose.yaml. Services are configured as items
+// docker_postgres, docker_redis and docker_nats; a question whose every
+// option is hidden is left out.
+func (p *DockerPlugin) Questions(projectPath, projectType string) ([]question.Question, error) {
+	var files []question.Option
+	defaultFiles := []string{}
+	for _, it := range settings.Filter(p.items, fileItems(), (*item).state) {
+		files = append(files, question.Option{Value: it.ID, Label: it.Label})
+		if it.Selected {
+			defaultFiles = append(defaultFiles, it.ID)
+		}
+	}
+
+	var svcs []question.Option
+	defaultSvcs := []string{}
+	for _, s := range services() {
+		offered, selected := p.items.Default("docker_"+s.ID, false)
+		if !offered {
+			continue
+		}
+		svcs = append(svcs, question.Option{Value: s.ID, Label: s.Label})
+		if selected {
+			defaultSvcs = append(defaultSvcs, s.ID)
+		}
+	}
+
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	qs := []question.Question{
+		{
+			ID:      "files",
+			Type:    question.MultiSelect,
+			Label:   "Select container files to generate",
+			Default: defaultFiles,
+			Options: files,
+		},
+		{
+			ID:      "final_stage",
+			Type:    question.Select,
+			Label:   "Final image",
+			Help:    "Both run the binary as a non-root user.",
+			Default: "distroless",
+			Options: []question.Option{
+				{Value: "distroless", Label: "distroless/static (CA certificates, tzdata, nonroot user)"},
+				{Value: "scratch", Label: "scratch (empty image, smallest)"},
+			},
+			When: &question.Condition{ID: "files", Equals: "docker_dockerfile"},
+		},
+	}
+	if len(svcs) > 0 {
+		qs = append(qs, question.Question{
+			ID:      "services",
+			Type:    question.MultiSelect,
+			Label:   "Companion services in compose.yaml",
+			Help:    "Their connection settings are added to .env.",
+			Default: defaultSvcs,
+			Options: svcs,
+			When:    &question.Condition{ID: "files", Equals: "docker_compose"},
+		})
+	}
+	return qs, nil
+}
+
+// goProject is what the Go Dockerfile needs to know about the project.
+type goProject struct {
+	GoVersion  string
+	Binary     string
+	Package    string
+	FinalStage string
+}
+
+// inspectGo reads go.mod for the binary name and the Go version, and picks
+// ./cmd/<binary> as the main package when it exists.
+func (p *DockerPlugin) inspectGo(projectPath string) goProject {
+	info := goProject{GoVersion: "1", Binary: filepath.Base(projectPath), Package: "."}
+
+	if mod, err := gomod.Read(p.fs, projectPath); err == nil {
+		if mod.Module != "" {
+			info.Binary = mod.Binary()
+		}
+		if v := mod.MinorVersion(); v != "" {
+			info.GoVersion = v
+		}
+	}
+
+	if fsys.Exists(p.fs, filepath.Join(projectPath, "cmd", info.Binary)) {
+		info.Package = "./cmd/" + info.Binary
+	}
+	return info
+}
+
+// Apply writes the selected files; existing files are kept. Settings for
+// the selected services are added to .env unless already defined.
+func (p *DockerPlugin) Apply(projectPath, projectType string, answers question.Answers) ([]string, error) {
+	selected := map[string]bool{}
+	for _, id := range answers.Strings("files") {
+		selected[id] = true
+	}
+
+	var chosen []service
+	for _, id := range answers.Strings("services") {
+		for _, s := range services() {
+			if s.ID == id {
+				chosen = append(chosen, s)
+			}
+		}
+	}
+
+	var summary []string
+	for _, it := range fileItems() {
+		if !selected[it.ID] {
+			continue
+		}
+
+		data, err := p.render(it.ID, projectPath, answers.String("final_stage"), chosen)
+		if err != nil {
+			return summary, fmt.Errorf("failed to render %s: %w", it.File, err)
+		}
+		action, err := conflict.Write(p.fs, filepath.Join(projectPath, it.File), data, 0o644, conflict.Skip)
+		if err != nil {
+			return summary, fmt.Errorf("failed to write %s: %w", it.File, err)
+		}
+		summary = append(summary, it.File+" "+action)
+	}
+
+	if selected["docker_compose"] && len(chosen) > 0 {
+		added, err := p.addEnv(projectPath, chosen)
+		if err != nil {
+			return summary, err
+		}
+		summary = append(summary, fmt.Sprintf("Added %d setting(s) to .env", added))
+	}
+
+	if j := journal.Of(p.fs); j != nil {
+		j.AddPlugin("docker")
+	}
+
+	if len(summary) == 0 {
+		summary = []string{"No files were selected."}
+	}
+	return summary, nil
+}
+
+func (p *DockerPlugin) render(id, projectPath, finalStage string, chosen []service) ([]byte, error) {
+	switch id {
+	case "docker_dockerfile":
+		info := p.inspectGo(projectPath)
+		info.FinalStage = finalStage
+		return render(goDockerfile, info)
+
+	case "docker_dockerignore":
+		return []byte(goDockerignore), nil
+
+	case "docker_compose":
+		var volumes []string
+		for _, s := range chosen {
+			if s.Volume != "" {
+				volumes = append(volumes, s.Volume)
+			}
+		}
+		envFile := len(chosen) > 0 || fsys.Exists(p.fs, filepath.Join(projectPath, ".env"))
+		return render(composeFile, struct {
+			Services []service
+			Volumes  []string
+			EnvFile  bool
+		}{chosen, volumes, envFile})
+	}
+	return nil, fmt.Errorf("unknown item %q", id)
+}
+
+// addEnv adds the services' settings to .env, and to .env.example when
+// the project has one, skipping keys already defined. It returns how many
+// were added to .env.
+func (p *DockerPlugin) addEnv(projectPath string, chosen []service) (int, error) {
+	var vars []envfile.Var
+	for _, s := range chosen {
+		for _, entry := range s.Env {
+			key, value, _ := strings.Cut(entry, "=")
+			vars = append(vars, envfile.Var{Key: key, Value: value})
+		}
+	}
+
+	example := fsys.Exists(p.fs, filepath.Join(projectPath, ".env.example"))
+	res, err := envfile.Write(p.fs, projectPath, "compose services", filepath.Base(projectPath), vars, example)
+	return res.Env, err
+}
+
+func (p *DockerPlugin) NewWizard(projectPath, projectType string) tea.Model {
+	return wizard.NewPostCreate(p, p.fs, "Docker and compose", projectPath, projectType)
+}
@@ -0,0 +1,74 @@
+package docker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/question"
+	"github.com/ezeqielle/pcli/internal/runner"
+	"github.com/ezeqielle/pcli/internal/settings"
+)
+
+func TestQuestionsWithoutServices(t *testing.T) {
+	items := settings.Items{
+		"docker_postgres": settings.Hidden,
+		"docker_redis":    settings.Hidden,
+		"docker_nats":     settings.Hidden,
+	}
+	qs, err := New(runner.NewFake(), fsys.NewMemory(), items).Questions("", "go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, q := range qs {
+		if q.ID == "services" {
+			t.Error("services is asked with every service hidden")
+		}
+		if err := q.Validate(); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestComposeEnvFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		services []string
+		env      bool
+		want     bool
+	}{
+		{"no services", nil, false, false},
+		{"services", []string{"postgres"}, false, true},
+		{"existing .env", nil, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := fsys.NewMemory()
+			if err := fs.MkdirAll("/p", 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if tt.env {
+				if err := fs.WriteFile("/p/.env", []byte("A=1\n"), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			answers := question.Answers{"files": []string{"docker_compose"}, "services": tt.services}
+			if _, err := New(runner.NewFake(), fs, nil).Apply("/p", "go", answers); err != nil {
+				t.Fatal(err)
+			}
+
+			compose, err := fs.ReadFile("/p/compose.yaml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Contains(string(compose), "env_file: .env"); got != tt.want {
+				t.Errorf("env_file in compose.yaml = %v, want %v:\n%s", got, tt.want, compose)
+			}
+			if tt.want && !fsys.Exists(fs, "/p/.env") {
+				t.Error("compose.yaml references a missing .env")
+			}
+		})
+	}
+}
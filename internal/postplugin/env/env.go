@@ -0,0 +1,234 @@
+package env
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ezeqielle/pcli/internal/conflict"
+	"github.com/ezeqielle/pcli/internal/envfile"
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/journal"
+	"github.com/ezeqielle/pcli/internal/pluginmeta"
+	"github.com/ezeqielle/pcli/internal/postplugin"
+	"github.com/ezeqielle/pcli/internal/question"
+	"github.com/ezeqielle/pcli/internal/runner"
+	"github.com/ezeqielle/pcli/internal/settings"
+	"github.com/ezeqielle/pcli/internal/version"
+	"github.com/ezeqielle/pcli/internal/wizard"
+)
+
+// EnvPlugin implements a post-create plugin that writes the project's
+// environment: .env with values and generated secrets, a committed
+// .env.example without them, and for Go a typed config loader.
+type EnvPlugin struct {
+	runner runner.Runner
+	fs     fsys.FS
+	items  settings.Items
+}
+
+// New returns the plugin; items overrides the default state of its presets.
+func New(r runner.Runner, fs fsys.FS, items settings.Items) *EnvPlugin {
+	return &EnvPlugin{runner: r, fs: fs, items: items}
+}
+
+func (p *EnvPlugin) ID() string {
+	return "env"
+}
+
+func (p *EnvPlugin) DisplayName() string {
+	return "Environment variables"
+}
+
+func (p *EnvPlugin) Metadata() pluginmeta.Metadata {
+	return pluginmeta.Metadata{
+		Version: version.Version,
+		Author:  "pcli",
+		Tags:    []string{"env", "config", "secrets"},
+		After:   []string{"global"},
+	}
+}
+
+// configPath is where the Go config loader is written.
+const configPath = "internal/config/config.go"
+
+// Inspect reports which of the plugin's files already exist in projectPath.
+func (p *EnvPlugin) Inspect(projectPath, projectType string) []postplugin.ItemStatus {
+	files := []postplugin.ItemStatus{
+		{ID: "env", Label: ".env", Path: ".env"},
+		{ID: "env_example", Label: ".env.example", Path: ".env.example"},
+	}
+	if projectType == "go" {
+		files = append(files, postplugin.ItemStatus{ID: "env_config", Label: "Go config loader", Path: configPath})
+	}
+	for i, f := range files {
+		files[i].Present = fsys.Exists(p.fs, filepath.Join(projectPath, filepath.FromSlash(f.Path)))
+	}
+	return files
+}
+
+// defaultPresets start selected; items env_<preset> override them.
+var defaultPresets = map[string]bool{"server": true, "log": true}
+
+// Questions asks for the presets, extra variables, whether to write
+// .env.example and, for Go, whether to generate the config loader.
+func (p *EnvPlugin) Questions(projectPath, projectType string) ([]question.Question, error) {
+	var presets []question.Option
+	defaults := []string{}
+	for _, pr := range envfile.Presets() {
+		offered, selected := p.items.Default("env_"+pr.ID, defaultPresets[pr.ID])
+		if !offered {
+			continue
+		}
+		presets = append(presets, question.Option{Value: pr.ID, Label: pr.Label})
+		if selected {
+			defaults = append(defaults, pr.ID)
+		}
+	}
+
+	qs := []question.Question{
+		{
+			ID:      "presets",
+			Type:    question.MultiSelect,
+			Label:   "Select variables to add",
+			Default: defaults,
+			Options: presets,
+		},
+		{
+			ID:      "custom",
+			Type:    question.Text,
+			Label:   "Extra variables (optional)",
+			Help:    "Comma-separated KEY or KEY=value. Keys with SECRET, PASSWORD or TOKEN, or ending in _KEY, get a random value.",
+			Pattern: `^\s*([A-Za-z_][A-Za-z0-9_]*(=[^,]*)?\s*(,\s*[A-Za-z_][A-Za-z0-9_]*(=[^,]*)?\s*)*)?$`,
+		},
+		{
+			ID:      "example",
+			Type:    question.Confirm,
+			Label:   "Write .env.example for the repository?",
+			Help:    "Same keys with descriptions and no values; .env itself is gitignored.",
+			Default: true,
+		},
+	}
+
+	if projectType == "go" {
+		qs = append(qs, question.Question{
+			ID:      "loader",
+			Type:    question.Confirm,
+			Label:   "Generate a typed config loader in " + configPath + "?",
+			Default: false,
+		})
+	}
+	return qs, nil
+}
+
+// group is a set of variables written under one section comment.
+type group struct {
+	section string
+	vars    []envfile.Var
+}
+
+// groups returns the variables of the answers, per preset, then the extra
+// ones.
+func groups(answers question.Answers) []group {
+	chosen := map[string]bool{}
+	for _, id := range answers.Strings("presets") {
+		chosen[id] = true
+	}
+
+	var out []group
+	for _, pr := range envfile.Presets() {
+		if chosen[pr.ID] {
+			section, _, _ := strings.Cut(pr.Label, " (")
+			out = append(out, group{section: section, vars: pr.Vars})
+		}
+	}
+
+	var custom []envfile.Var
+	for _, entry := range strings.Split(answers.String("custom"), ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(entry), "=")
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		custom = append(custom, envfile.Var{Key: key, Value: value, Secret: value == "" && envfile.LooksSecret(key)})
+	}
+	if len(custom) > 0 {
+		out = append(out, group{section: "Custom", vars: custom})
+	}
+	return out
+}
+
+// Apply adds the variables missing from .env and .env.example, makes sure
+// .env is gitignored and writes the config loader, keeping an existing one.
+func (p *EnvPlugin) Apply(projectPath, projectType string, answers question.Answers) ([]string, error) {
+	name := filepath.Base(projectPath)
+	example := answers.Bool("example")
+
+	var total envfile.Result
+	var all []envfile.Var
+	seen := map[string]bool{}
+	for _, g := range groups(answers) {
+		res, err := envfile.Write(p.fs, projectPath, g.section, name, g.vars, example)
+		if err != nil {
+			return nil, err
+		}
+		total.Env += res.Env
+		total.Example += res.Example
+		for _, v := range g.vars {
+			if !seen[v.Key] {
+				seen[v.Key] = true
+				all = append(all, v)
+			}
+		}
+	}
+
+	summary := []string{fmt.Sprintf(".env: %d variable(s) added", total.Env)}
+	if example {
+		summary = append(summary, fmt.Sprintf(".env.example: %d variable(s) added", total.Example))
+	}
+
+	action, err := conflict.Write(p.fs, filepath.Join(projectPath, ".gitignore"), []byte("# Local settings\n.env\n"), 0o644, conflict.Merge)
+	if err != nil {
+		return summary, fmt.Errorf("failed to update .gitignore: %w", err)
+	}
+	if action != "already up to date" {
+		summary = append(summary, ".gitignore "+action)
+	}
+
+	if projectType == "go" && answers.Bool("loader") {
+		line, err := p.writeLoader(projectPath, name, all)
+		if err != nil {
+			return summary, err
+		}
+		summary = append(summary, line)
+	}
+
+	if j := journal.Of(p.fs); j != nil {
+		j.AddPlugin("env")
+	}
+	return summary, nil
+}
+
+func (p *EnvPlugin) writeLoader(projectPath, name string, vars []envfile.Var) (string, error) {
+	data, err := renderConfig(vars, name)
+	if err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", configPath, err)
+	}
+
+	path := filepath.Join(projectPath, filepath.FromSlash(configPath))
+	if err := p.fs.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create internal/config: %w", err)
+	}
+	action, err := conflict.Write(p.fs, path, data, 0o644, conflict.Skip)
+	if err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", configPath, err)
+	}
+	return configPath + " " + action, nil
+}
+
+func (p *EnvPlugin) NewWizard(projectPath, projectType string) tea.Model {
+	return wizard.NewPostCreate(p, p.fs, "Environment variables", projectPath, projectType)
+}
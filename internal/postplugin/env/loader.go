@@ -0,0 +1,184 @@
+package env
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/ezeqielle/pcli/internal/envfile"
+)
+
+// configFile is the internal/config package generated into Go projects.
+var configFile = template.Must(template.New("config.go").Parse(`// Package config loads the application's settings from the environment.
+// It was generated by pcli from the project's .env schema; edit freely.
+package config
+
+import (
+{{- if .Fmt}}
+	"fmt"
+{{- end}}
+	"os"
+{{- if .Strconv}}
+	"strconv"
+{{- end}}
+)
+
+// Config holds the settings; .env.example documents them.
+type Config struct {
+{{- range .Fields}}
+	// {{.Name}} reads {{.Key}}{{if .Description}} – {{.Description}}{{end}}.
+	{{.Name}} {{.GoType}}
+{{- end}}
+}
+
+// Load reads the settings from the environment. Unset variables fall back
+// to the values pcli first wrote to .env; secrets have no default and must
+// be set.
+func Load() (Config, error) {
+	var c Config
+	var err error
+{{range .Fields}}
+	if c.{{.Name}}, err = {{.Getter}}; err != nil {
+		return c, err
+	}
+{{- end}}
+	return c, nil
+}
+{{- if .Lookup}}
+
+func lookup(key, def string) (string, error) {
+	if v, ok := os.LookupEnv(key); ok {
+		return v, nil
+	}
+	return def, nil
+}
+{{- end}}
+{{- if .Required}}
+
+func required(key string) (string, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return "", fmt.Errorf("config: %s must be set", key)
+	}
+	return v, nil
+}
+{{- end}}
+{{- if .Int}}
+
+func intVar(key string, def int) (int, error) {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("config: %s: %w", key, err)
+	}
+	return n, nil
+}
+{{- end}}
+{{- if .Bool}}
+
+func boolVar(key string, def bool) (bool, error) {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return def, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("config: %s: %w", key, err)
+	}
+	return b, nil
+}
+{{- end}}
+`))
+
+type field struct {
+	Key         string
+	Name        string
+	Description string
+	GoType      string
+	Getter      string
+}
+
+// renderConfig generates the loader for vars; name replaces {{name}} in
+// defaults.
+func renderConfig(vars []envfile.Var, name string) ([]byte, error) {
+	data := struct {
+		Fields                                    []field
+		Fmt, Strconv, Lookup, Required, Int, Bool bool
+	}{}
+
+	for _, v := range vars {
+		f := field{
+			Key:         v.Key,
+			Name:        goName(v.Key),
+			Description: lowerFirst(v.Description),
+			GoType:      "string",
+		}
+		def := strings.ReplaceAll(v.Value, "{{name}}", name)
+
+		switch {
+		case v.Secret:
+			f.Getter = fmt.Sprintf("required(%q)", v.Key)
+			data.Required = true
+		case v.Type == envfile.Int:
+			n, _ := strconv.Atoi(def)
+			f.GoType, f.Getter = "int", fmt.Sprintf("intVar(%q, %d)", v.Key, n)
+			data.Int = true
+		case v.Type == envfile.Bool:
+			b, _ := strconv.ParseBool(def)
+			f.GoType, f.Getter = "bool", fmt.Sprintf("boolVar(%q, %t)", v.Key, b)
+			data.Bool = true
+		default:
+			f.Getter = fmt.Sprintf("lookup(%q, %q)", v.Key, def)
+			data.Lookup = true
+		}
+		data.Fields = append(data.Fields, f)
+	}
+	data.Fmt = data.Required || data.Int || data.Bool
+	data.Strconv = data.Int || data.Bool
+
+	var b bytes.Buffer
+	if err := configFile.Execute(&b, data); err != nil {
+		return nil, err
+	}
+	return format.Source(b.Bytes())
+}
+
+// initialisms keep Go's casing in field names, e.g. DATABASE_URL →
+// DatabaseURL.
+var initialisms = map[string]bool{
+	"API": true, "DB": true, "DNS": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true,
+	"JWT": true, "SQL": true, "SSH": true, "TLS": true, "TTL": true, "URI": true, "URL": true, "UUID": true,
+}
+
+// goName turns an environment key into an exported field name.
+func goName(key string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(strings.ToUpper(key), "_") {
+		if part == "" {
+			continue
+		}
+		if initialisms[part] {
+			b.WriteString(part)
+			continue
+		}
+		b.WriteString(part[:1] + strings.ToLower(part[1:]))
+	}
+	name := b.String()
+	if name == "" || name[0] < 'A' || name[0] > 'Z' {
+		name = "V" + name
+	}
+	return name
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
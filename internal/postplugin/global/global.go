@@ -2,19 +2,40 @@ package global
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ezeqielle/pcli/internal/conflict"
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/gitignore"
+	"github.com/ezeqielle/pcli/internal/gomod"
+	"github.com/ezeqielle/pcli/internal/journal"
+	"github.com/ezeqielle/pcli/internal/nav"
+	"github.com/ezeqielle/pcli/internal/pluginmeta"
+	"github.com/ezeqielle/pcli/internal/postplugin"
+	"github.com/ezeqielle/pcli/internal/question"
+	"github.com/ezeqielle/pcli/internal/runner"
+	"github.com/ezeqielle/pcli/internal/settings"
+	"github.com/ezeqielle/pcli/internal/taskrunner"
+	"github.com/ezeqielle/pcli/internal/ui"
+	"github.com/ezeqielle/pcli/internal/version"
+	"github.com/ezeqielle/pcli/internal/wizard"
 )
 
 // GlobalPlugin implements a post-create plugin that
-// adds global + type-specific files/folders.
-type GlobalPlugin struct{}
+// adds files/folders useful to every project type.
+type GlobalPlugin struct {
+	runner runner.Runner
+	fs     fsys.FS
+	items  settings.Items
+}
 
-func New() *GlobalPlugin {
-	return &GlobalPlugin{}
+// New returns the plugin; items overrides the default state of its items.
+func New(r runner.Runner, fs fsys.FS, items settings.Items) *GlobalPlugin {
+	return &GlobalPlugin{runner: r, fs: fs, items: items}
 }
 
 func (p *GlobalPlugin) ID() string {
@@ -25,8 +46,155 @@ func (p *GlobalPlugin) DisplayName() string {
 	return "Global post-create populater"
 }
 
+// Inspect reports which of the plugin's items already exist in projectPath.
+// The task runner item is present when any supported task file is.
+func (p *GlobalPlugin) Inspect(projectPath, projectType string) []postplugin.ItemStatus {
+	var out []postplugin.ItemStatus
+	for _, it := range globalItems() {
+		target := it.File
+		if target == "" {
+			target = it.Dir + "/"
+		}
+		if it.ID == "global_makefile" {
+			for _, f := range taskrunner.Formats() {
+				if fsys.Exists(p.fs, filepath.Join(projectPath, f.File)) {
+					target = f.File
+					break
+				}
+			}
+		}
+		out = append(out, postplugin.ItemStatus{
+			ID:      it.ID,
+			Label:   it.Label,
+			Path:    target,
+			Present: fsys.Exists(p.fs, filepath.Join(projectPath, target)),
+		})
+	}
+	return out
+}
+
+func (p *GlobalPlugin) Metadata() pluginmeta.Metadata {
+	return pluginmeta.Metadata{
+		Version: version.Version,
+		Author:  "pcli",
+		Tags:    []string{"files", "docs", "env"},
+	}
+}
+
+// Questions describes the plugin for non-interactive runs: which items to
+// add, the .gitignore extras and the policy for files that already exist.
+// Asking per file is only possible in the wizard.
+func (p *GlobalPlugin) Questions(projectPath, projectType string) ([]question.Question, error) {
+	var options []question.Option
+	defaults := []string{}
+	for _, it := range settings.Filter(p.items, globalItems(), (*item).state) {
+		options = append(options, question.Option{Value: it.ID, Label: it.Label})
+		if it.Selected {
+			defaults = append(defaults, it.ID)
+		}
+	}
+
+	var extras []question.Option
+	defaultExtras := []string{}
+	for _, row := range gitignoreExtras(projectType, p.items) {
+		extras = append(extras, question.Option{Value: row.ID, Label: row.Label})
+		if row.Selected {
+			defaultExtras = append(defaultExtras, row.ID)
+		}
+	}
+
+	var formats []question.Option
+	for _, f := range taskrunner.Formats() {
+		formats = append(formats, question.Option{Value: f.ID, Label: f.Label + " (" + f.File + ")"})
+	}
+
+	var policies []question.Option
+	for _, pol := range conflict.Policies {
+		if pol != conflict.Ask {
+			policies = append(policies, question.Option{Value: pol.String()})
+		}
+	}
+
+	return []question.Question{
+		{
+			ID:      "items",
+			Type:    question.MultiSelect,
+			Label:   "Select global items to add",
+			Default: defaults,
+			Options: options,
+		},
+		{
+			ID:      "gitignore",
+			Type:    question.MultiSelect,
+			Label:   "Extra .gitignore fragments",
+			Help:    "Added to the fragments of the project type: " + strings.Join(gitignore.ForType(projectType), ", ") + ".",
+			Default: defaultExtras,
+			Options: extras,
+			When:    &question.Condition{ID: "items", Equals: "global_gitignore"},
+		},
+		{
+			ID:      "task_runner",
+			Type:    question.Select,
+			Label:   "Task runner",
+			Default: taskrunner.Formats()[0].ID,
+			Options: formats,
+			When:    &question.Condition{ID: "items", Equals: "global_makefile"},
+		},
+		{
+			ID:      "policy",
+			Type:    question.Select,
+			Label:   "What to do with files that already exist",
+			Default: conflict.Skip.String(),
+			Options: policies,
+		},
+	}, nil
+}
+
+// Apply adds the selected items with the chosen conflict policy.
+func (p *GlobalPlugin) Apply(projectPath, projectType string, answers question.Answers) ([]string, error) {
+	m := NewModel(p.runner, p.fs, projectPath, projectType, p.items)
+
+	selected := map[string]bool{}
+	for _, id := range answers.Strings("items") {
+		selected[id] = true
+	}
+	for i := range m.list.Items {
+		m.list.Items[i].Selected = selected[m.list.Items[i].ID]
+	}
+
+	extras := map[string]bool{}
+	for _, id := range answers.Strings("gitignore") {
+		extras[id] = true
+	}
+	for i := range m.ignore.Items {
+		m.ignore.Items[i].Selected = extras[m.ignore.Items[i].ID]
+	}
+
+	if id := answers.String("task_runner"); id != "" {
+		f, ok := taskrunner.Lookup(id)
+		if !ok {
+			return nil, fmt.Errorf("unknown task runner %q", id)
+		}
+		m.setTaskFormat(f)
+	}
+
+	if s := answers.String("policy"); s != "" {
+		policy, err := conflict.Parse(s)
+		if err != nil {
+			return nil, err
+		}
+		m.policy = policy
+	}
+
+	summary, err := m.applySelections()
+	if j := journal.Of(p.fs); j != nil && err == nil {
+		j.AddPlugin("global")
+	}
+	return summary, err
+}
+
 func (p *GlobalPlugin) NewWizard(projectPath, projectType string) tea.Model {
-	return NewModel(projectPath, projectType)
+	return NewModel(p.runner, p.fs, projectPath, projectType, p.items)
 }
 
 // ---------- Wizard model ----------
@@ -35,7 +203,10 @@ type step int
 
 const (
 	stepGlobal step = iota
-	stepTypeSpecific
+	stepConflict
+	stepPreview
+	stepGitignore
+	stepRollback
 	stepDone
 )
 
@@ -43,237 +214,566 @@ type item struct {
 	ID       string
 	Label    string
 	Selected bool
+
+	// File is the project-relative file the item writes; Dir the folder it creates.
+	File string
+	Dir  string
+	// Policy overrides the wizard-wide conflict policy when HasPolicy is set.
+	Policy    conflict.Policy
+	HasPolicy bool
+}
+
+// state gives settings.Filter the item's ID and selection.
+func (it *item) state() (string, *bool) {
+	return it.ID, &it.Selected
 }
 
+// fileContents holds what each file item writes; .gitignore is composed
+// from fragments and the task file rendered for the project type instead.
+var fileContents = map[string]string{
+	"global_readme": "# Project Title\n\nProject description.\n",
+}
+
+// listChrome is the number of lines the item screen shows around the
+// list: headers, default policy and key help.
+const listChrome = 14
+
 type Model struct {
 	step step
 
+	runner runner.Runner
+	fs     fsys.FS
+
 	projectPath string
 	projectType string
 
-	cursor       int
-	globalItems  []item
-	typeItems    []item
+	globalItems []item
+	list        ui.Checklist
+	// ignore holds the .gitignore fragments picked on top of those of the
+	// project type.
+	ignore ui.Checklist
+	// taskFormat is the task runner the global_makefile item writes for.
+	taskFormat   taskrunner.Format
 	errMsg       string
 	applySummary []string
+
+	// policy is the conflict policy for items without their own override.
+	policy conflict.Policy
+	// conflicts are the existing files whose policy is Ask, resolved one by
+	// one in stepConflict; resolved holds the user's answer per item ID.
+	conflicts []item
+	resolved  map[string]conflict.Policy
+
+	// rollback is shown in stepRollback; rollbackFrom is the step it
+	// resumes.
+	rollback     wizard.Rollback
+	rollbackFrom step
+
+	// tree is the projected project tree for the current selections;
+	// pager shows a file item's rendered content in stepPreview.
+	tree         []ui.TreeEntry
+	pager        ui.Pager
+	previewTitle string
+	previewLines int
+
+	width, height int
 }
 
-func NewModel(projectPath, projectType string) Model {
-	globalItems := []item{
-		{ID: "global_env", Label: "Create .env file", Selected: false},
-		{ID: "global_notes", Label: "Create notes/ folder", Selected: false},
-		{ID: "global_readme", Label: "Create README.md file", Selected: false},
-		{ID: "global_gitignore", Label: "Create .gitignore file", Selected: false},
-		{ID: "global_makefile", Label: "Create Makefile", Selected: false},
+// globalItems returns the items offered for every project type.
+func globalItems() []item {
+	return []item{
+		{ID: "global_notes", Label: "Create notes/ folder", Selected: false, Dir: "notes"},
+		{ID: "global_readme", Label: "Create README.md file", Selected: false, File: "README.md"},
+		{ID: "global_gitignore", Label: "Create .gitignore file", Selected: false, File: ".gitignore", Policy: conflict.Merge, HasPolicy: true},
+		{ID: "global_makefile", Label: "Create Makefile", Selected: false, File: "Makefile"},
 	}
+}
 
-	var typeItems []item
+func NewModel(r runner.Runner, fs fsys.FS, projectPath, projectType string, items settings.Items) Model {
+	offered := settings.Filter(items, globalItems(), (*item).state)
 
-	switch projectType {
-	case "go":
-		typeItems = []item{
-			{ID: "go_cmd", Label: "Create cmd/ folder", Selected: true},
-			{ID: "go_internal", Label: "Create internal/ folder", Selected: true},
-			{ID: "go_pkg", Label: "Create pkg/ folder", Selected: true},
-			{ID: "go_tests", Label: "Create tests/ folder", Selected: false},
-			{ID: "go_gen", Label: "Create gen/ folder", Selected: false},
-			{ID: "go_api", Label: "Create api/ folder", Selected: false},
-		}
-	default:
-		typeItems = nil
+	rows := make([]ui.CheckItem, len(offered))
+	for i, it := range offered {
+		rows[i] = ui.CheckItem{ID: it.ID, Label: it.Label, Selected: it.Selected}
 	}
 
-	return Model{
+	m := Model{
 		step:        stepGlobal,
+		runner:      r,
+		fs:          fs,
 		projectPath: projectPath,
 		projectType: projectType,
-		cursor:      0,
-		globalItems: globalItems,
-		typeItems:   typeItems,
+		globalItems: offered,
+		list:        ui.NewChecklist(rows),
+		ignore:      ui.NewChecklist(gitignoreExtras(projectType, items)),
+		taskFormat:  taskrunner.Formats()[0],
+		policy:      conflict.Skip,
+	}
+	m.refreshNotes()
+	m.refreshPreview()
+	return m
+}
+
+// refreshNotes shows each file item's conflict policy next to its label,
+// and the fragments .gitignore is composed of.
+func (m *Model) refreshNotes() {
+	for i, it := range m.globalItems {
+		if it.File != "" {
+			m.list.Items[i].Note = "(if exists: " + m.policyLabel(it) + ")"
+		}
+		if it.ID == "global_gitignore" {
+			m.list.Items[i].Note += " " + strings.Join(m.gitignoreFragments(), ", ")
+		}
+	}
+}
+
+// gitignoreExtras returns the fragments offered on top of those of the
+// project type; items gitignore_<id> set their default.
+func gitignoreExtras(projectType string, items settings.Items) []ui.CheckItem {
+	auto := map[string]bool{}
+	for _, id := range gitignore.ForType(projectType) {
+		auto[id] = true
+	}
+
+	var rows []ui.CheckItem
+	for _, f := range gitignore.Fragments() {
+		if auto[f.ID] {
+			continue
+		}
+		offered, selected := items.Default("gitignore_"+f.ID, false)
+		if !offered {
+			continue
+		}
+		rows = append(rows, ui.CheckItem{ID: f.ID, Label: f.Label, Note: "(" + string(f.Kind) + ")", Selected: selected})
+	}
+	return rows
+}
+
+// gitignoreFragments returns the fragments .gitignore is composed of: the
+// project type's, then the picked extras.
+func (m Model) gitignoreFragments() []string {
+	ids := gitignore.ForType(m.projectType)
+	for _, row := range m.ignore.Items {
+		if row.Selected {
+			ids = append(ids, row.ID)
+		}
+	}
+	return ids
+}
+
+// setTaskFormat points the task runner item at the file of format f.
+func (m *Model) setTaskFormat(f taskrunner.Format) {
+	m.taskFormat = f
+	for i, it := range m.globalItems {
+		if it.ID == "global_makefile" {
+			m.globalItems[i].File = f.File
+			m.globalItems[i].Label = "Create " + f.File
+			m.list.Items[i].Label = m.globalItems[i].Label
+		}
 	}
 }
 
+// taskProject describes the project to the task file templates. For Go,
+// go.mod gives the module and binary name, and ./cmd/<binary> is the main
+// package when it exists.
+func (m Model) taskProject() taskrunner.Project {
+	name := filepath.Base(m.projectPath)
+	p := taskrunner.Project{Name: name, Binary: name, Module: name, Main: "."}
+
+	if m.projectType != "go" {
+		return p
+	}
+	if mod, err := gomod.Read(m.fs, m.projectPath); err == nil && mod.Module != "" {
+		p.Module, p.Binary = mod.Module, mod.Binary()
+	}
+	if fsys.Exists(m.fs, filepath.Join(m.projectPath, "cmd", p.Binary)) {
+		p.Main = "./cmd/" + p.Binary
+	}
+	return p
+}
+
+// content returns what a file item writes.
+func (m Model) content(it item) ([]byte, error) {
+	switch it.ID {
+	case "global_gitignore":
+		return gitignore.Compose(m.gitignoreFragments())
+	case "global_makefile":
+		return taskrunner.Render(m.taskFormat, m.projectType, m.taskProject())
+	}
+	return []byte(fileContents[it.ID]), nil
+}
+
 func (m Model) Init() tea.Cmd {
 	return nil
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.list.SetHeight(max(msg.Height-listChrome, 3))
+		m.ignore.SetHeight(max(msg.Height-gitignoreChrome, 3))
+		m.pager.SetSize(m.pagerSize())
+
 	case tea.KeyMsg:
 		switch m.step {
 
 		case stepGlobal:
 			switch msg.String() {
-			case "up", "k":
+			case "c":
 				if len(m.globalItems) == 0 {
 					return m, nil
 				}
-				m.cursor--
-				if m.cursor < 0 {
-					m.cursor = len(m.globalItems) - 1
-				}
+				cyclePolicy(&m.globalItems[m.list.Cursor()])
+				m.refreshNotes()
+				m.refreshPreview()
 				return m, nil
 
-			case "down", "j":
-				if len(m.globalItems) == 0 {
-					return m, nil
-				}
-				m.cursor++
-				if m.cursor >= len(m.globalItems) {
-					m.cursor = 0
-				}
+			case "p":
+				m.policy = m.policy.Next()
+				m.refreshNotes()
+				m.refreshPreview()
+				return m, nil
+
+			case "g":
+				m.step = stepGitignore
 				return m, nil
 
-			case " ":
+			case "f":
+				m.setTaskFormat(m.taskFormat.Next())
+				m.refreshPreview()
+				return m, nil
+
+			case "v":
 				if len(m.globalItems) == 0 {
 					return m, nil
 				}
-				m.globalItems[m.cursor].Selected = !m.globalItems[m.cursor].Selected
+				it := m.globalItems[m.list.Cursor()]
+				if it.File == "" {
+					return m, nil
+				}
+				content, note := m.renderFile(it)
+				if content == "" {
+					content = "(empty file)"
+				}
+				m.previewLines = strings.Count(content, "\n") + 1
+				w, h := m.pagerSize()
+				m.pager = ui.NewPager(content, w, h)
+				m.previewTitle = note
+				m.step = stepPreview
 				return m, nil
 
 			case "enter":
-				m.step = stepTypeSpecific
-				m.cursor = 0
-				return m, nil
+				m.conflicts = m.pendingConflicts()
+				m.resolved = map[string]conflict.Policy{}
+				if len(m.conflicts) > 0 {
+					m.step = stepConflict
+					return m, nil
+				}
+				return m.apply()
 
-			case "esc":
+			case "s":
 				m.step = stepDone
 				m.applySummary = []string{"Skipped population."}
 				return m, nil
 
+			case "esc":
+				return m, nav.Back
+
 			case "ctrl+c":
-				return m, tea.Quit
+				return m.cancel()
 			}
 
-		case stepTypeSpecific:
+			var cmd tea.Cmd
+			m.list, cmd = m.list.Update(msg)
+			m.refreshPreview()
+			return m, cmd
+
+		case stepGitignore:
 			switch msg.String() {
-			case "up", "k":
-				if len(m.typeItems) == 0 {
-					return m, nil
-				}
-				m.cursor--
-				if m.cursor < 0 {
-					m.cursor = len(m.typeItems) - 1
-				}
+			case "esc", "enter", "g":
+				m.step = stepGlobal
+				m.refreshNotes()
+				m.refreshPreview()
 				return m, nil
 
-			case "down", "j":
-				if len(m.typeItems) == 0 {
-					return m, nil
-				}
-				m.cursor++
-				if m.cursor >= len(m.typeItems) {
-					m.cursor = 0
-				}
-				return m, nil
+			case "ctrl+c":
+				return m.cancel()
+			}
 
-			case " ":
-				if len(m.typeItems) == 0 {
-					return m, nil
-				}
-				m.typeItems[m.cursor].Selected = !m.typeItems[m.cursor].Selected
+			var cmd tea.Cmd
+			m.ignore, cmd = m.ignore.Update(msg)
+			return m, cmd
+
+		case stepPreview:
+			switch msg.String() {
+			case "esc", "q", "v":
+				m.step = stepGlobal
 				return m, nil
 
-			case "enter":
-				summary, err := m.applySelections()
-				m.applySummary = summary
-				if err != nil {
-					m.errMsg = err.Error()
+			case "ctrl+c":
+				return m.cancel()
+			}
+
+			var cmd tea.Cmd
+			m.pager, cmd = m.pager.Update(msg)
+			return m, cmd
+
+		case stepConflict:
+			choices := map[string]conflict.Policy{
+				"s": conflict.Skip,
+				"o": conflict.Overwrite,
+				"b": conflict.Backup,
+				"a": conflict.Append,
+				"m": conflict.Merge,
+			}
+
+			if p, ok := choices[msg.String()]; ok {
+				m.resolved[m.conflicts[0].ID] = p
+				m.conflicts = m.conflicts[1:]
+				if len(m.conflicts) > 0 {
+					return m, nil
 				}
-				m.step = stepDone
-				return m, nil
+				return m.apply()
+			}
 
+			switch msg.String() {
 			case "esc":
+				m.conflicts = nil
 				m.step = stepGlobal
-				m.cursor = 0
 				return m, nil
 
 			case "ctrl+c":
-				return m, tea.Quit
+				return m.cancel()
 			}
 
+		case stepRollback:
+			var cmd tea.Cmd
+			m.rollback, cmd = m.rollback.Update(msg)
+
+			switch m.rollback.State() {
+			case wizard.RolledBack:
+				m.applySummary = m.rollback.Summary()
+				m.errMsg = ""
+				if err := m.rollback.Err(); err != nil {
+					m.errMsg = "rollback incomplete: " + err.Error()
+				}
+				m.step = stepDone
+
+			case wizard.Kept:
+				m.step = stepDone
+
+			case wizard.Resumed:
+				m.step = m.rollbackFrom
+			}
+			return m, cmd
+
 		case stepDone:
-			// any key exits
-			return m, tea.Quit
+			if msg.String() == "esc" {
+				return m, nav.Back
+			}
+			// any other key moves on to the next post-create plugin, unless
+			// the project was just rolled back
+			if m.rollback.State() == wizard.RolledBack {
+				return m, postplugin.Abort
+			}
+			return m, postplugin.Finish
 		}
 	}
 
 	return m, nil
 }
 
+// apply writes the selections and moves to the result or rollback step.
+func (m Model) apply() (tea.Model, tea.Cmd) {
+	summary, err := m.applySelections()
+	m.applySummary = summary
+	if j := journal.Of(m.fs); j != nil && err == nil {
+		j.AddPlugin("global")
+	}
+	if err != nil {
+		m.errMsg = err.Error()
+		if wizard.CanRollback(m.fs) {
+			m.rollback = wizard.NewRollback(m.fs, "Post-create", "Applying post-create items failed: "+err.Error())
+			m.step = stepRollback
+			return m, nil
+		}
+	}
+	m.step = stepDone
+	return m, nil
+}
+
+// cyclePolicy steps a file item's override through every policy and back to
+// inheriting the wizard-wide policy.
+func cyclePolicy(it *item) {
+	if it.File == "" {
+		return
+	}
+	if !it.HasPolicy {
+		it.Policy, it.HasPolicy = conflict.Policies[0], true
+		return
+	}
+	if it.Policy == conflict.Policies[len(conflict.Policies)-1] {
+		it.HasPolicy = false
+		return
+	}
+	it.Policy = it.Policy.Next()
+}
+
+func (m Model) effectivePolicy(it item) conflict.Policy {
+	if p, ok := m.resolved[it.ID]; ok {
+		return p
+	}
+	if it.HasPolicy {
+		return it.Policy
+	}
+	return m.policy
+}
+
+// pendingConflicts returns the selected file items whose target exists
+// and whose policy asks the user.
+func (m Model) pendingConflicts() []item {
+	var out []item
+	for _, it := range m.globalItems {
+		if !m.list.IsSelected(it.ID) || it.File == "" || m.effectivePolicy(it) != conflict.Ask {
+			continue
+		}
+		if fsys.Exists(m.fs, filepath.Join(m.projectPath, it.File)) {
+			out = append(out, it)
+		}
+	}
+	return out
+}
+
+// cancel offers to roll back the run when quitting after pcli has already
+// created files; otherwise it quits right away.
+func (m Model) cancel() (tea.Model, tea.Cmd) {
+	if !wizard.CanRollback(m.fs) {
+		return m, tea.Quit
+	}
+	m.rollback = wizard.CancelRollback(m.fs, "Post-create")
+	m.rollbackFrom = m.step
+	m.step = stepRollback
+	return m, nil
+}
+
 func (m Model) View() string {
 	switch m.step {
 	case stepGlobal:
 		return m.viewGlobal()
-	case stepTypeSpecific:
-		return m.viewTypeSpecific()
+	case stepConflict:
+		return m.viewConflict()
+	case stepPreview:
+		return m.viewPreview()
+	case stepGitignore:
+		return m.viewGitignore()
+	case stepRollback:
+		return m.rollback.View()
 	case stepDone:
 		return m.viewDone()
 	}
 	return ""
 }
 
+// pagerSize returns the size of the file preview: the file's length, up
+// to what the terminal fits.
+func (m Model) pagerSize() (int, int) {
+	if m.width == 0 {
+		return 80, min(m.previewLines, 15)
+	}
+	return m.width, min(m.previewLines, max(m.height-previewChrome, 3))
+}
+
+// previewChrome is the number of lines the file preview shows around the
+// pager: headers and key help.
+const previewChrome = 8
+
+// treeBeside is the terminal width from which the projected tree is shown
+// next to the item list rather than below it.
+const treeBeside = 100
+
 func (m Model) viewGlobal() string {
 	var b strings.Builder
 
-	b.WriteString("Post-create – Global options\n\n")
+	b.WriteString(ui.Header("Post-create – Global options", 1, 2))
 	b.WriteString("Project: " + m.projectPath + "\n\n")
-	b.WriteString("Select global items to add (space to toggle, enter to continue):\n\n")
+	b.WriteString("Select global items to add (space to toggle, enter to apply):\n\n")
+	list := m.list.View() + "\nExisting files: " + m.policy.String() + "\n"
 
-	if len(m.globalItems) == 0 {
-		b.WriteString("  (no global options yet)\n")
+	tree := "Preview:\n\n" + ui.FileTree(filepath.Base(m.projectPath), m.tree) + "\n" + ui.TreeLegend + "\n"
+	if m.width >= treeBeside {
+		listCol := lipgloss.NewStyle().Width(lipgloss.Width(list) + 4).Render(list)
+		b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, listCol, tree))
 	} else {
-		for i, it := range m.globalItems {
-			cursor := " "
-			if i == m.cursor {
-				cursor = ">"
-			}
-			check := " "
-			if it.Selected {
-				check = "x"
-			}
-			b.WriteString(fmt.Sprintf("%s [%s] %s\n", cursor, check, it.Label))
-		}
+		b.WriteString(list + "\n" + tree)
 	}
 
 	if m.errMsg != "" {
-		b.WriteString("\nError: " + m.errMsg + "\n")
+		b.WriteString(ui.ErrorLine(m.errMsg))
 	}
 
-	b.WriteString("\n[↑/↓] Move  [space] Toggle  [enter] Next  [esc] Skip  [ctrl+c] Quit\n")
+	b.WriteString(ui.Help(ui.ChecklistKeys, "[c] Item policy", "[p] Default policy", "[v] View file", "[g] .gitignore extras", "[f] Task runner", "[enter] Apply", "[s] Skip", "[esc] Back", "[ctrl+c] Quit"))
 
 	return b.String()
 }
 
-func (m Model) viewTypeSpecific() string {
+// gitignoreChrome is the number of lines the fragment screen shows around
+// its list.
+const gitignoreChrome = 10
+
+func (m Model) viewGitignore() string {
 	var b strings.Builder
 
-	b.WriteString("Post-create – Type-specific options\n\n")
-	b.WriteString("Project: " + m.projectPath + "\n")
-	b.WriteString("Type: " + m.projectType + "\n\n")
-	b.WriteString("Select type-specific items to add (space to toggle, enter to apply):\n\n")
+	b.WriteString(ui.Header("Post-create – .gitignore", 0, 0))
+	b.WriteString("Always included for this project: " + strings.Join(gitignore.ForType(m.projectType), ", ") + "\n\n")
+	b.WriteString("Pick extra fragments (duplicate patterns are written once):\n\n")
+	b.WriteString(m.ignore.View())
+	b.WriteString(ui.Help(ui.ChecklistKeys, "[enter/esc] Done", "[ctrl+c] Quit"))
 
-	if len(m.typeItems) == 0 {
-		b.WriteString("  (no type-specific options for this project type yet)\n")
-	} else {
-		for i, it := range m.typeItems {
-			cursor := " "
-			if i == m.cursor {
-				cursor = ">"
-			}
-			check := " "
-			if it.Selected {
-				check = "x"
-			}
-			b.WriteString(fmt.Sprintf("%s [%s] %s\n", cursor, check, it.Label))
-		}
+	return b.String()
+}
+
+func (m Model) viewPreview() string {
+	var b strings.Builder
+
+	b.WriteString(ui.Header("Post-create – Preview", 0, 0))
+	b.WriteString(m.previewTitle + "\n\n")
+	b.WriteString(m.pager.View())
+	b.WriteString(ui.Help(ui.PagerKeys, "[esc] Back", "[ctrl+c] Quit"))
+
+	return b.String()
+}
+
+func (m Model) policyLabel(it item) string {
+	if it.HasPolicy {
+		return it.Policy.String()
 	}
+	return "default: " + m.policy.String()
+}
 
-	if m.errMsg != "" {
-		b.WriteString("\nError: " + m.errMsg + "\n")
+func (m Model) viewConflict() string {
+	var b strings.Builder
+
+	it := m.conflicts[0]
+	path := filepath.Join(m.projectPath, it.File)
+
+	b.WriteString("Post-create – " + it.File + " already exists\n\n")
+	b.WriteString("Project: " + m.projectPath + "\n\n")
+	b.WriteString("Diff (- existing, + pcli):\n\n")
+
+	existing, err := m.fs.ReadFile(path)
+	if err != nil {
+		b.WriteString("  (could not read existing file: " + err.Error() + ")\n")
+	}
+	data, err := m.content(it)
+	if err != nil {
+		b.WriteString("  (could not render " + it.File + ": " + err.Error() + ")\n")
+	}
+	for _, line := range conflict.Diff(existing, data) {
+		b.WriteString("  " + line.String() + "\n")
 	}
 
-	b.WriteString("\n[↑/↓] Move  [space] Toggle  [enter] Apply  [esc] Back  [ctrl+c] Quit\n")
+	b.WriteString(ui.Help("[s] Skip", "[o] Overwrite", "[b] Backup + overwrite", "[a] Append", "[m] Merge", "[esc] Back", "[ctrl+c] Quit"))
 
 	return b.String()
 }
@@ -281,7 +781,7 @@ func (m Model) viewTypeSpecific() string {
 func (m Model) viewDone() string {
 	var b strings.Builder
 
-	b.WriteString("Post-create – Result\n\n")
+	b.WriteString(ui.Header("Post-create – Result", 2, 2))
 	b.WriteString("Project: " + m.projectPath + "\n\n")
 
 	if len(m.applySummary) == 0 {
@@ -292,11 +792,16 @@ func (m Model) viewDone() string {
 		}
 	}
 
+	if p, dryRun := fsys.PlanOf(m.fs); dryRun {
+		b.WriteString("\nDry run – nothing was written. Plan:\n\n")
+		b.WriteString(p.Render())
+	}
+
 	if m.errMsg != "" {
-		b.WriteString("\nError: " + m.errMsg + "\n")
+		b.WriteString(ui.ErrorLine(m.errMsg))
 	}
 
-	b.WriteString("\n[any key] Exit\n")
+	b.WriteString(ui.Help("[esc] Back", "[any key] Continue"))
 
 	return b.String()
 }
@@ -304,115 +809,51 @@ func (m Model) viewDone() string {
 func (m *Model) applySelections() ([]string, error) {
 	var summary []string
 
-	// Global items
 	for _, it := range m.globalItems {
-		if !it.Selected {
+		if !m.list.IsSelected(it.ID) {
 			continue
 		}
 
-		switch it.ID {
-		case "global_env":
-			envPath := filepath.Join(m.projectPath, ".env")
-			if _, err := os.Stat(envPath); err == nil {
-				summary = append(summary, ".env already exists (skipped)")
-				continue
-			}
-			if err := os.WriteFile(envPath, []byte(""), 0o644); err != nil {
-				return summary, fmt.Errorf("failed to create .env: %w", err)
-			}
-			summary = append(summary, "Created .env file")
-
-		case "global_notes":
-			notesPath := filepath.Join(m.projectPath, "notes")
-			if err := os.MkdirAll(notesPath, 0o755); err != nil {
-				return summary, fmt.Errorf("failed to create notes/ folder: %w", err)
+		switch {
+		case it.File != "":
+			line, err := m.writeFileItem(it)
+			if err != nil {
+				return summary, err
 			}
-			summary = append(summary, "Created notes/ folder")
+			summary = append(summary, line)
 
-		case "global_readme":
-			readmePath := filepath.Join(m.projectPath, "README.md")
-			if _, err := os.Stat(readmePath); err == nil {
-				summary = append(summary, "README.md already exists (skipped)")
-				continue
+		case it.Dir != "":
+			if err := m.fs.MkdirAll(filepath.Join(m.projectPath, it.Dir), 0o755); err != nil {
+				return summary, fmt.Errorf("failed to create %s/: %w", it.Dir, err)
 			}
-			if err := os.WriteFile(readmePath, []byte("# Project Title\n\nProject description.\n"), 0o644); err != nil {
-				return summary, fmt.Errorf("failed to create README.md: %w", err)
-			}
-			summary = append(summary, "Created README.md file")
-
-		case "global_gitignore":
-			gitignorePath := filepath.Join(m.projectPath, ".gitignore")
-			if _, err := os.Stat(gitignorePath); err == nil {
-				summary = append(summary, ".gitignore already exists (skipped)")
-				continue
-			}
-			if err := os.WriteFile(gitignorePath, []byte("# Ignore files\n.env\nnotes/\n"), 0o644); err != nil {
-				return summary, fmt.Errorf("failed to create .gitignore: %w", err)
-			}
-			summary = append(summary, "Created .gitignore file")
-
-		case "global_makefile":
-			makefilePath := filepath.Join(m.projectPath, "Makefile")
-			if _, err := os.Stat(makefilePath); err == nil {
-				summary = append(summary, "Makefile already exists (skipped)")
-				continue
-			}
-			if err := os.WriteFile(makefilePath, []byte("all:\n\t@echo \"Build commands go here\"\n"), 0o644); err != nil {
-				return summary, fmt.Errorf("failed to create Makefile: %w", err)
-			}
-			summary = append(summary, "Created Makefile")
+			summary = append(summary, "Created "+it.Dir+"/ folder")
 		}
 	}
 
-	// Type-specific items
-	for _, it := range m.typeItems {
-		if !it.Selected {
-			continue
-		}
-
-		switch m.projectType {
-		case "go":
-			switch it.ID {
-			case "go_cmd":
-				if err := os.MkdirAll(filepath.Join(m.projectPath, "cmd"), 0o755); err != nil {
-					return summary, fmt.Errorf("failed to create cmd/: %w", err)
-				}
-				summary = append(summary, "Created cmd/ folder")
+	if len(summary) == 0 {
+		summary = []string{"No items were selected."}
+	}
 
-			case "go_internal":
-				if err := os.MkdirAll(filepath.Join(m.projectPath, "internal"), 0o755); err != nil {
-					return summary, fmt.Errorf("failed to create internal/: %w", err)
-				}
-				summary = append(summary, "Created internal/ folder")
+	return summary, nil
+}
 
-			case "go_pkg":
-				if err := os.MkdirAll(filepath.Join(m.projectPath, "pkg"), 0o755); err != nil {
-					return summary, fmt.Errorf("failed to create pkg/: %w", err)
-				}
-				summary = append(summary, "Created pkg/ folder")
+// writeFileItem writes a file item, resolving an existing target with the
+// item's conflict policy, and describes the action taken.
+func (m *Model) writeFileItem(it item) (string, error) {
+	path := filepath.Join(m.projectPath, it.File)
+	policy := m.effectivePolicy(it)
 
-			case "go_tests":
-				if err := os.MkdirAll(filepath.Join(m.projectPath, "tests"), 0o755); err != nil {
-					return summary, fmt.Errorf("failed to create tests/: %w", err)
-				}
-				summary = append(summary, "Created tests/ folder")
-			case "go_gen":
-				if err := os.MkdirAll(filepath.Join(m.projectPath, "gen"), 0o755); err != nil {
-					return summary, fmt.Errorf("failed to create gen/: %w", err)
-				}
-				summary = append(summary, "Created gen/ folder")
-			case "go_api":
-				if err := os.MkdirAll(filepath.Join(m.projectPath, "api"), 0o755); err != nil {
-					return summary, fmt.Errorf("failed to create api/: %w", err)
-				}
-				summary = append(summary, "Created api/ folder")
-			}
-		}
+	data, err := m.content(it)
+	if err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", it.File, err)
 	}
-
-	if len(summary) == 0 {
-		summary = []string{"No items were selected."}
+	action, err := conflict.Write(m.fs, path, data, 0o644, policy)
+	if err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", it.File, err)
 	}
 
-	return summary, nil
+	if action == "created" {
+		return "Created " + it.File, nil
+	}
+	return fmt.Sprintf("%s %s (policy: %s)", it.File, action, policy), nil
 }
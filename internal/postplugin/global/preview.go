@@ -0,0 +1,122 @@
+package global
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/ezeqielle/pcli/internal/conflict"
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/plan"
+	"github.com/ezeqielle/pcli/internal/ui"
+)
+
+// previewDepth is how deep the preview lists files already in the project.
+const previewDepth = 2
+
+// refreshPreview applies the current selections to a dry-run copy of the
+// filesystem and keeps the projected project tree.
+func (m *Model) refreshPreview() {
+	p := plan.New()
+	dry := *m
+	dry.fs = fsys.NewDryRun(m.fs, p)
+	// a failing item stops the simulation; the tree shows what came before
+	_, _ = dry.applySelections()
+
+	m.tree = m.projectedTree(p)
+}
+
+// projectedTree merges the files already in the project with the plan of
+// the dry run.
+func (m Model) projectedTree(p *plan.Plan) []ui.TreeEntry {
+	entries := map[string]ui.TreeEntry{}
+	for _, e := range existingEntries(m.fs, m.projectPath, "", previewDepth) {
+		entries[e.Path] = e
+	}
+
+	policies := map[string]conflict.Policy{}
+	for _, it := range m.globalItems {
+		if it.File != "" && m.list.IsSelected(it.ID) {
+			policies[it.File] = m.effectivePolicy(it)
+		}
+	}
+
+	written := map[string]bool{}
+	for _, op := range p.Ops() {
+		rel, err := filepath.Rel(m.projectPath, op.Path)
+		if err != nil || rel == "." {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+
+		switch op.Kind {
+		case plan.KindDir:
+			if _, ok := entries[rel]; !ok {
+				entries[rel] = ui.TreeEntry{Path: rel, Dir: true, Status: ui.TreeNew}
+			}
+		case plan.KindFile:
+			written[rel] = true
+			if op.Exists {
+				entries[rel] = ui.TreeEntry{Path: rel, Status: ui.TreeChanged, Note: policies[rel].String()}
+			} else {
+				entries[rel] = ui.TreeEntry{Path: rel, Status: ui.TreeNew}
+			}
+		}
+	}
+
+	// selected files that exist and are not written are kept as they are
+	for file, policy := range policies {
+		if e, ok := entries[file]; ok && !written[file] {
+			e.Status, e.Note = ui.TreeKept, policy.String()
+			entries[file] = e
+		}
+	}
+
+	out := make([]ui.TreeEntry, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Path < out[j].Path })
+	return out
+}
+
+// existingEntries lists the project's files and directories down to depth
+// levels, relative to root.
+func existingEntries(fs fsys.FS, root, rel string, depth int) []ui.TreeEntry {
+	if depth == 0 {
+		return nil
+	}
+	list, err := fs.ReadDir(filepath.Join(root, filepath.FromSlash(rel)))
+	if err != nil {
+		return nil
+	}
+
+	var out []ui.TreeEntry
+	for _, d := range list {
+		p := d.Name()
+		if rel != "" {
+			p = rel + "/" + d.Name()
+		}
+		out = append(out, ui.TreeEntry{Path: p, Dir: d.IsDir(), Status: ui.TreeExisting})
+		if d.IsDir() {
+			out = append(out, existingEntries(fs, root, p, depth-1)...)
+		}
+	}
+	return out
+}
+
+// renderFile returns the content a file item would leave on disk with its
+// conflict policy, and a note describing it.
+func (m Model) renderFile(it item) (string, string) {
+	dry := m
+	dry.fs = fsys.NewDryRun(m.fs, plan.New())
+
+	line, err := dry.writeFileItem(it)
+	if err != nil {
+		return "", err.Error()
+	}
+	data, err := dry.fs.ReadFile(filepath.Join(m.projectPath, it.File))
+	if err != nil {
+		return "", err.Error()
+	}
+	return string(data), line
+}
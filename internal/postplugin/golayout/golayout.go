@@ -0,0 +1,139 @@
+package golayout
+
+import (
+	"fmt"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/journal"
+	"github.com/ezeqielle/pcli/internal/pluginmeta"
+	"github.com/ezeqielle/pcli/internal/postplugin"
+	"github.com/ezeqielle/pcli/internal/question"
+	"github.com/ezeqielle/pcli/internal/runner"
+	"github.com/ezeqielle/pcli/internal/settings"
+	"github.com/ezeqielle/pcli/internal/version"
+	"github.com/ezeqielle/pcli/internal/wizard"
+)
+
+// LayoutPlugin implements a post-create plugin that
+// adds the conventional Go project folders.
+type LayoutPlugin struct {
+	runner runner.Runner
+	fs     fsys.FS
+	items  settings.Items
+}
+
+// New returns the plugin; items overrides the default state of its folders.
+func New(r runner.Runner, fs fsys.FS, items settings.Items) *LayoutPlugin {
+	return &LayoutPlugin{runner: r, fs: fs, items: items}
+}
+
+func (p *LayoutPlugin) ID() string {
+	return "go_layout"
+}
+
+func (p *LayoutPlugin) DisplayName() string {
+	return "Go project layout"
+}
+
+func (p *LayoutPlugin) Metadata() pluginmeta.Metadata {
+	return pluginmeta.Metadata{
+		Version:      version.Version,
+		Author:       "pcli",
+		Tags:         []string{"go", "layout"},
+		ProjectTypes: []string{"go"},
+		After:        []string{"global"},
+	}
+}
+
+type item struct {
+	ID       string
+	Label    string
+	Selected bool
+	Dir      string
+}
+
+// layoutItems returns the folders offered for Go projects.
+func layoutItems() []item {
+	return []item{
+		{ID: "go_cmd", Label: "Create cmd/ folder", Selected: true, Dir: "cmd"},
+		{ID: "go_internal", Label: "Create internal/ folder", Selected: true, Dir: "internal"},
+		{ID: "go_pkg", Label: "Create pkg/ folder", Selected: true, Dir: "pkg"},
+		{ID: "go_tests", Label: "Create tests/ folder", Selected: false, Dir: "tests"},
+		{ID: "go_gen", Label: "Create gen/ folder", Selected: false, Dir: "gen"},
+		{ID: "go_api", Label: "Create api/ folder", Selected: false, Dir: "api"},
+	}
+}
+
+// state gives settings.Filter the item's ID and selection.
+func (it *item) state() (string, *bool) {
+	return it.ID, &it.Selected
+}
+
+// Inspect reports which of the layout folders already exist in projectPath.
+func (p *LayoutPlugin) Inspect(projectPath, projectType string) []postplugin.ItemStatus {
+	var out []postplugin.ItemStatus
+	for _, it := range layoutItems() {
+		out = append(out, postplugin.ItemStatus{
+			ID:      it.ID,
+			Label:   it.Label,
+			Path:    it.Dir + "/",
+			Present: fsys.Exists(p.fs, filepath.Join(projectPath, it.Dir)),
+		})
+	}
+	return out
+}
+
+// Questions asks which folders to create; hidden folders are not offered.
+func (p *LayoutPlugin) Questions(projectPath, projectType string) ([]question.Question, error) {
+	var options []question.Option
+	defaults := []string{}
+	for _, it := range settings.Filter(p.items, layoutItems(), (*item).state) {
+		options = append(options, question.Option{Value: it.ID, Label: it.Label})
+		if it.Selected {
+			defaults = append(defaults, it.ID)
+		}
+	}
+
+	return []question.Question{{
+		ID:      "folders",
+		Type:    question.MultiSelect,
+		Label:   "Select folders to create",
+		Default: defaults,
+		Options: options,
+	}}, nil
+}
+
+// Apply creates the selected folders.
+func (p *LayoutPlugin) Apply(projectPath, projectType string, answers question.Answers) ([]string, error) {
+	selected := map[string]bool{}
+	for _, id := range answers.Strings("folders") {
+		selected[id] = true
+	}
+
+	var summary []string
+	for _, it := range layoutItems() {
+		if !selected[it.ID] {
+			continue
+		}
+		if err := p.fs.MkdirAll(filepath.Join(projectPath, it.Dir), 0o755); err != nil {
+			return summary, fmt.Errorf("failed to create %s/: %w", it.Dir, err)
+		}
+		summary = append(summary, "Created "+it.Dir+"/ folder")
+	}
+
+	if j := journal.Of(p.fs); j != nil {
+		j.AddPlugin("go_layout")
+	}
+
+	if len(summary) == 0 {
+		summary = []string{"No folders were selected."}
+	}
+	return summary, nil
+}
+
+func (p *LayoutPlugin) NewWizard(projectPath, projectType string) tea.Model {
+	return wizard.NewPostCreate(p, p.fs, "Go project layout", projectPath, projectType)
+}
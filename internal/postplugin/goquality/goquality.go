@@ -0,0 +1,376 @@
+package goquality
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ezeqielle/pcli/internal/conflict"
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/gomod"
+	"github.com/ezeqielle/pcli/internal/journal"
+	"github.com/ezeqielle/pcli/internal/pluginmeta"
+	"github.com/ezeqielle/pcli/internal/postplugin"
+	"github.com/ezeqielle/pcli/internal/question"
+	"github.com/ezeqielle/pcli/internal/runner"
+	"github.com/ezeqielle/pcli/internal/settings"
+	"github.com/ezeqielle/pcli/internal/version"
+	"github.com/ezeqielle/pcli/internal/wizard"
+)
+
+// QualityPlugin implements a post-create plugin that sets up linting and
+// formatting for Go projects: golangci-lint, .editorconfig, tool
+// dependencies and pre-commit hooks.
+type QualityPlugin struct {
+	runner runner.Runner
+	fs     fsys.FS
+	items  settings.Items
+}
+
+// New returns the plugin; items overrides the default state of its items
+// and tools.
+func New(r runner.Runner, fs fsys.FS, items settings.Items) *QualityPlugin {
+	return &QualityPlugin{runner: r, fs: fs, items: items}
+}
+
+func (p *QualityPlugin) ID() string {
+	return "go_quality"
+}
+
+func (p *QualityPlugin) DisplayName() string {
+	return "Go linting and code quality"
+}
+
+func (p *QualityPlugin) Metadata() pluginmeta.Metadata {
+	return pluginmeta.Metadata{
+		Version:      version.Version,
+		Author:       "pcli",
+		Tags:         []string{"go", "lint", "golangci-lint", "pre-commit"},
+		ProjectTypes: []string{"go"},
+		After:        []string{"global", "go_layout"},
+	}
+}
+
+type item struct {
+	ID       string
+	Label    string
+	Selected bool
+	File     string
+}
+
+// state gives settings.Filter the item's ID and selection.
+func (it *item) state() (string, *bool) {
+	return it.ID, &it.Selected
+}
+
+// qualityItems returns what the plugin can set up; go_tools writes go.mod
+// tool directives or tools.go depending on the Go version.
+func qualityItems() []item {
+	return []item{
+		{ID: "go_golangci", Label: "golangci-lint config (.golangci.yml)", Selected: true, File: ".golangci.yml"},
+		{ID: "go_editorconfig", Label: ".editorconfig", Selected: true, File: ".editorconfig"},
+		{ID: "go_tools", Label: "Tool dependencies (go tool, or tools.go before Go 1.24)", Selected: false},
+		{ID: "go_precommit", Label: "pre-commit hooks (.pre-commit-config.yaml)", Selected: false, File: ".pre-commit-config.yaml"},
+	}
+}
+
+// tool is a development tool pinned in go.mod.
+type tool struct {
+	ID      string
+	Label   string
+	Package string
+	// Module is the module providing Package, queried for its latest version.
+	Module string
+	// Selected is the tool's default.
+	Selected bool
+}
+
+func tools() []tool {
+	return []tool{
+		{ID: "golangci-lint", Label: "golangci-lint", Package: "github.com/golangci/golangci-lint/v2/cmd/golangci-lint", Module: "github.com/golangci/golangci-lint/v2", Selected: true},
+		{ID: "govulncheck", Label: "govulncheck", Package: "golang.org/x/vuln/cmd/govulncheck", Module: "golang.org/x/vuln", Selected: true},
+		{ID: "stringer", Label: "stringer", Package: "golang.org/x/tools/cmd/stringer", Module: "golang.org/x/tools"},
+		{ID: "gofumpt", Label: "gofumpt", Package: "mvdan.cc/gofumpt", Module: "mvdan.cc/gofumpt"},
+		{ID: "mockgen", Label: "mockgen", Package: "go.uber.org/mock/mockgen", Module: "go.uber.org/mock"},
+	}
+}
+
+// pin is a tool resolved to the version added to go.mod.
+type pin struct {
+	tool
+	Version string
+}
+
+// Inspect reports which of the plugin's items are already set up in
+// projectPath.
+func (p *QualityPlugin) Inspect(projectPath, projectType string) []postplugin.ItemStatus {
+	var out []postplugin.ItemStatus
+	for _, it := range qualityItems() {
+		status := postplugin.ItemStatus{ID: it.ID, Label: it.Label, Path: it.File}
+		if it.File != "" {
+			status.Present = fsys.Exists(p.fs, filepath.Join(projectPath, it.File))
+		} else {
+			status.Path, status.Present = p.toolsPresent(projectPath)
+		}
+		out = append(out, status)
+	}
+	return out
+}
+
+// toolsPresent looks for tools.go, then for tool directives in go.mod.
+func (p *QualityPlugin) toolsPresent(projectPath string) (string, bool) {
+	if fsys.Exists(p.fs, filepath.Join(projectPath, "tools.go")) {
+		return "tools.go", true
+	}
+	mod, err := gomod.Read(p.fs, projectPath)
+	return "go.mod", err == nil && len(mod.Tools) > 0
+}
+
+// Questions asks which items to set up, the golangci-lint preset and the
+// tools to pin. Tools are configured as items go_tool_<id>.
+func (p *QualityPlugin) Questions(projectPath, projectType string) ([]question.Question, error) {
+	var items []question.Option
+	defaultItems := []string{}
+	for _, it := range settings.Filter(p.items, qualityItems(), (*item).state) {
+		items = append(items, question.Option{Value: it.ID, Label: it.Label})
+		if it.Selected {
+			defaultItems = append(defaultItems, it.ID)
+		}
+	}
+
+	var toolOptions []question.Option
+	defaultTools := []string{}
+	for _, t := range tools() {
+		offered, selected := p.items.Default("go_tool_"+t.ID, t.Selected)
+		if !offered {
+			continue
+		}
+		toolOptions = append(toolOptions, question.Option{Value: t.ID, Label: t.Label + " (" + t.Package + ")"})
+		if selected {
+			defaultTools = append(defaultTools, t.ID)
+		}
+	}
+
+	toolsHelp := "From Go 1.24, tool directives added with go get -tool; before, imports in tools.go. Tools whose latest release needs a newer Go are skipped."
+	if projectPath != "" {
+		if mod, err := gomod.Read(p.fs, projectPath); err == nil && mod.AtLeast(1, 24) {
+			toolsHelp = "Added as tool directives with go get -tool; run them with go tool <name>."
+		} else {
+			toolsHelp = "Go before 1.24: imported from tools.go and required with go get; run them with go run <package>."
+		}
+	}
+
+	qs := []question.Question{
+		{
+			ID:      "items",
+			Type:    question.MultiSelect,
+			Label:   "Select code quality items",
+			Default: defaultItems,
+			Options: items,
+		},
+		{
+			ID:      "preset",
+			Type:    question.Select,
+			Label:   "golangci-lint preset",
+			Default: "standard",
+			Options: []question.Option{
+				{Value: "minimal", Label: "minimal – the standard linters and gofmt"},
+				{Value: "standard", Label: "standard – plus revive, gocritic, errorlint … and goimports"},
+				{Value: "strict", Label: "strict – plus gosec, wrapcheck, gocyclo … and gofumpt"},
+			},
+			When: &question.Condition{ID: "items", Equals: "go_golangci"},
+		},
+	}
+	if len(toolOptions) > 0 {
+		qs = append(qs, question.Question{
+			ID:      "tools",
+			Type:    question.MultiSelect,
+			Label:   "Tools to pin",
+			Help:    toolsHelp,
+			Default: defaultTools,
+			Options: toolOptions,
+			When:    &question.Condition{ID: "items", Equals: "go_tools"},
+		})
+	}
+	return qs, nil
+}
+
+// Apply pins the tools first, so the pre-commit hook can run the pinned
+// golangci-lint, then writes the selected files; existing files are kept.
+func (p *QualityPlugin) Apply(projectPath, projectType string, answers question.Answers) ([]string, error) {
+	selected := map[string]bool{}
+	for _, id := range answers.Strings("items") {
+		selected[id] = true
+	}
+
+	// the module path and go version only matter to these two
+	var mod gomod.Info
+	if selected["go_tools"] || selected["go_golangci"] {
+		m, err := gomod.Read(p.fs, projectPath)
+		if err != nil {
+			return nil, err
+		}
+		mod = m
+	}
+
+	var summary []string
+	lint := "golangci-lint"
+
+	if selected["go_tools"] {
+		var chosen []tool
+		for _, id := range answers.Strings("tools") {
+			for _, t := range tools() {
+				if t.ID == id {
+					chosen = append(chosen, t)
+				}
+			}
+		}
+
+		pinned, lines, err := p.pinTools(projectPath, mod, chosen)
+		summary = append(summary, lines...)
+		if err != nil {
+			return summary, err
+		}
+
+		for _, t := range pinned {
+			if t.ID == "golangci-lint" {
+				if mod.AtLeast(1, 24) {
+					lint = "go tool golangci-lint"
+				} else {
+					lint = "go run " + t.Package
+				}
+			}
+		}
+	}
+
+	for _, it := range qualityItems() {
+		if !selected[it.ID] || it.File == "" {
+			continue
+		}
+
+		data, err := p.render(it.ID, answers.String("preset"), mod.Module, lint)
+		if err != nil {
+			return summary, fmt.Errorf("failed to render %s: %w", it.File, err)
+		}
+		action, err := conflict.Write(p.fs, filepath.Join(projectPath, it.File), data, 0o644, conflict.Skip)
+		if err != nil {
+			return summary, fmt.Errorf("failed to write %s: %w", it.File, err)
+		}
+		summary = append(summary, it.File+" "+action)
+	}
+
+	if j := journal.Of(p.fs); j != nil {
+		j.AddPlugin("go_quality")
+	}
+
+	if len(summary) == 0 {
+		summary = []string{"No items were selected."}
+	}
+	return summary, nil
+}
+
+// pinTools adds the tools to go.mod: as tool directives from Go 1.24, or
+// through a tools.go file before. Each tool is pinned at its latest
+// release, unless that release needs a newer Go than the go directive:
+// adding it would raise the directive, so the tool is skipped instead.
+func (p *QualityPlugin) pinTools(projectPath string, mod gomod.Info, chosen []tool) ([]pin, []string, error) {
+	if len(chosen) == 0 {
+		return nil, []string{"No tools were selected."}, nil
+	}
+
+	var pinned []pin
+	var summary []string
+	for _, t := range chosen {
+		version, goVersion, err := latest(projectPath, t.Module)
+		if err != nil {
+			return nil, summary, err
+		}
+		if !mod.Supports(goVersion) {
+			summary = append(summary, fmt.Sprintf("%s skipped: %s@%s requires go %s, go.mod has go %s",
+				t.ID, t.Module, version, goVersion, mod.GoVersion))
+			continue
+		}
+		pinned = append(pinned, pin{tool: t, Version: version})
+	}
+	if len(pinned) == 0 {
+		return nil, summary, nil
+	}
+
+	if mod.AtLeast(1, 24) {
+		for _, t := range pinned {
+			cmd := runner.Cmd("go", "get", "-tool", t.Package+"@"+t.Version).In(projectPath)
+			if out, err := p.runner.Run(cmd); err != nil {
+				return nil, summary, fmt.Errorf("go get -tool %s failed: %v\n%s", t.Package, err, string(out))
+			}
+			summary = append(summary, "go.mod: tool "+t.ID+" "+t.Version+" added")
+		}
+		return pinned, summary, nil
+	}
+
+	packages := make([]string, 0, len(pinned))
+	for _, t := range pinned {
+		packages = append(packages, t.Package)
+	}
+	sort.Strings(packages)
+
+	data, err := render(toolsFile, packages)
+	if err != nil {
+		return nil, summary, fmt.Errorf("failed to render tools.go: %w", err)
+	}
+	action, err := conflict.Write(p.fs, filepath.Join(projectPath, "tools.go"), data, 0o644, conflict.Skip)
+	if err != nil {
+		return nil, summary, fmt.Errorf("failed to write tools.go: %w", err)
+	}
+	summary = append(summary, "tools.go "+action)
+
+	for _, t := range pinned {
+		cmd := runner.Cmd("go", "get", t.Package+"@"+t.Version).In(projectPath)
+		if out, err := p.runner.Run(cmd); err != nil {
+			return nil, summary, fmt.Errorf("go get %s failed: %v\n%s", t.Package, err, string(out))
+		}
+		summary = append(summary, "go.mod: "+t.ID+" "+t.Version+" required")
+	}
+	return pinned, summary, nil
+}
+
+// latest asks the module proxy for the latest release of module and the go
+// version it declares. The query changes nothing, so it runs directly
+// rather than through the session runner.
+func latest(projectPath, module string) (version, goVersion string, err error) {
+	cmd := runner.Cmd("go", "list", "-m", "-json", module+"@latest").In(projectPath)
+	out, err := runner.NewExec().Run(cmd)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to look up %s@latest: %v\n%s", module, err, string(out))
+	}
+
+	var info struct {
+		Version   string
+		GoVersion string
+	}
+	if err := json.Unmarshal(out, &info); err != nil {
+		return "", "", fmt.Errorf("failed to look up %s@latest: %w", module, err)
+	}
+	return info.Version, info.GoVersion, nil
+}
+
+func (p *QualityPlugin) render(id, preset, module, lint string) ([]byte, error) {
+	switch id {
+	case "go_golangci":
+		if preset == "" {
+			preset = "standard"
+		}
+		return render(golangciFile, struct{ Preset, Module string }{preset, module})
+	case "go_editorconfig":
+		return []byte(editorconfig), nil
+	case "go_precommit":
+		return render(precommitFile, struct{ Lint string }{lint})
+	}
+	return nil, fmt.Errorf("unknown item %q", id)
+}
+
+func (p *QualityPlugin) NewWizard(projectPath, projectType string) tea.Model {
+	return wizard.NewPostCreate(p, p.fs, "Go linting and code quality", projectPath, projectType)
+}
@@ -0,0 +1,137 @@
+package goquality
+
+import (
+	"bytes"
+	"text/template"
+)
+
+// golangciFile is a golangci-lint v2 configuration. Every preset builds on
+// the linters of the one before.
+var golangciFile = template.Must(template.New(".golangci.yml").Parse(`# golangci-lint configuration ({{.Preset}} preset), see https://golangci-lint.run
+version: "2"
+
+linters:
+  default: standard
+{{- if ne .Preset "minimal"}}
+  enable:
+    - bodyclose
+    - errorlint
+    - gocritic
+    - misspell
+    - revive
+    - unconvert
+    - unparam
+{{- if eq .Preset "strict"}}
+    - errname
+    - exhaustive
+    - gocyclo
+    - gosec
+    - nilerr
+    - nolintlint
+    - prealloc
+    - wrapcheck
+  settings:
+    gocyclo:
+      min-complexity: 15
+    nolintlint:
+      require-explanation: true
+      require-specific: true
+{{- end}}
+  exclusions:
+    presets:
+      - comments
+      - std-error-handling
+{{- if eq .Preset "strict"}}
+    rules:
+      - path: _test\.go
+        linters:
+          - gosec
+          - wrapcheck
+{{- end}}
+{{- end}}
+
+formatters:
+  enable:
+{{- if eq .Preset "strict"}}
+    - gofumpt
+{{- else}}
+    - gofmt
+{{- end}}
+{{- if ne .Preset "minimal"}}
+    - goimports
+  settings:
+    goimports:
+      local-prefixes:
+        - {{.Module}}
+{{- end}}
+`))
+
+// editorconfig matches gofmt for Go files and keeps tabs in Makefiles.
+const editorconfig = `root = true
+
+[*]
+charset = utf-8
+end_of_line = lf
+insert_final_newline = true
+trim_trailing_whitespace = true
+indent_style = space
+indent_size = 2
+
+[*.go]
+indent_style = tab
+indent_size = 4
+
+[{Makefile,*.mk}]
+indent_style = tab
+
+[*.md]
+trim_trailing_whitespace = false
+`
+
+// precommitFile runs the checks with the pre-commit framework, using the
+// toolchain installed locally.
+var precommitFile = template.Must(template.New(".pre-commit-config.yaml").Parse(`# Install the hooks with "pre-commit install"; check every file with
+# "pre-commit run --all-files".
+repos:
+  - repo: local
+    hooks:
+      - id: gofmt
+        name: gofmt
+        entry: gofmt -l -w
+        language: system
+        types: [go]
+      - id: go-vet
+        name: go vet
+        entry: go vet ./...
+        language: system
+        types: [go]
+        pass_filenames: false
+      - id: golangci-lint
+        name: golangci-lint
+        entry: {{.Lint}} run
+        language: system
+        types: [go]
+        pass_filenames: false
+`))
+
+// toolsFile pins tool dependencies in go.mod before Go 1.24.
+var toolsFile = template.Must(template.New("tools.go").Parse(`//go:build tools
+
+// Package tools pins the versions of the development tools in go.mod; run
+// them with "go run <package>".
+package tools
+
+import (
+{{- range .}}
+	_ "{{.}}"
+{{- end}}
+)
+`))
+
+func render(t *template.Template, data any) ([]byte, error) {
+	var b bytes.Buffer
+	if err := t.Execute(&b, data); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,394 @@
+package license
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ezeqielle/pcli/internal/conflict"
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/journal"
+	"github.com/ezeqielle/pcli/internal/pluginmeta"
+	"github.com/ezeqielle/pcli/internal/postplugin"
+	"github.com/ezeqielle/pcli/internal/question"
+	"github.com/ezeqielle/pcli/internal/runner"
+	"github.com/ezeqielle/pcli/internal/settings"
+	"github.com/ezeqielle/pcli/internal/version"
+	"github.com/ezeqielle/pcli/internal/wizard"
+)
+
+// LicensePlugin implements a post-create plugin that writes a LICENSE file
+// with an SPDX license, and optionally marks the project's source files and
+// manifests with it.
+type LicensePlugin struct {
+	runner runner.Runner
+	query  runner.Runner
+	fs     fsys.FS
+	items  settings.Items
+}
+
+// New returns the plugin; items overrides the default license
+// (license_<SPDX id>) and the defaults of license_headers and
+// license_manifests. query runs the read-only git queries: it must be
+// neither journaled nor dry-run, so a dry run still finds the holder.
+func New(r, query runner.Runner, fs fsys.FS, items settings.Items) *LicensePlugin {
+	return &LicensePlugin{runner: r, query: query, fs: fs, items: items}
+}
+
+func (p *LicensePlugin) ID() string {
+	return "license"
+}
+
+func (p *LicensePlugin) DisplayName() string {
+	return "License"
+}
+
+// Metadata runs the plugin after the plugins that generate source files, so
+// SPDX headers reach them too.
+func (p *LicensePlugin) Metadata() pluginmeta.Metadata {
+	return pluginmeta.Metadata{
+		Version: version.Version,
+		Author:  "pcli",
+		Tags:    []string{"license", "spdx"},
+		After:   []string{"global", "go_layout", "docker", "devenv"},
+	}
+}
+
+// Inspect reports whether the project already has a LICENSE file.
+func (p *LicensePlugin) Inspect(projectPath, projectType string) []postplugin.ItemStatus {
+	return []postplugin.ItemStatus{{
+		ID:      "license",
+		Label:   "LICENSE",
+		Path:    "LICENSE",
+		Present: fsys.Exists(p.fs, filepath.Join(projectPath, "LICENSE")),
+	}}
+}
+
+// Questions asks for the license, its copyright year and holder, and
+// whether to add SPDX headers and manifest fields. The holder defaults to
+// git's user.name. Hidden licenses are not offered, and hidden headers or
+// manifests are not asked for.
+func (p *LicensePlugin) Questions(projectPath, projectType string) ([]question.Question, error) {
+	var options []question.Option
+	def := ""
+	for _, l := range licenses() {
+		offered, selected := p.items.Default("license_"+l.ID, false)
+		if !offered {
+			continue
+		}
+		options = append(options, question.Option{Value: l.ID, Label: l.Label})
+		if selected && def == "" {
+			def = l.ID
+		}
+	}
+	if len(options) == 0 {
+		return nil, fmt.Errorf("every license is hidden by the configuration")
+	}
+	if def == "" {
+		def = options[0].Value
+		for _, o := range options {
+			if o.Value == "MIT" {
+				def = o.Value
+			}
+		}
+	}
+
+	holder := p.gitUser(projectPath)
+	if holder == "" && projectPath != "" {
+		holder = "The " + filepath.Base(projectPath) + " authors"
+	}
+
+	qs := []question.Question{
+		{
+			ID:      "license",
+			Type:    question.Select,
+			Label:   "License",
+			Default: def,
+			Options: options,
+		},
+		{
+			ID:       "holder",
+			Type:     question.Text,
+			Label:    "Copyright holder",
+			Default:  holder,
+			Required: true,
+		},
+		{
+			ID:      "year",
+			Type:    question.Text,
+			Label:   "Copyright year",
+			Help:    "A year or a range, e.g. 2024-2026.",
+			Default: strconv.Itoa(time.Now().Year()),
+			Pattern: `^[0-9]{4}(-[0-9]{4})?$`,
+		},
+	}
+
+	if offered, selected := p.items.Default("license_headers", false); offered {
+		qs = append(qs, question.Question{
+			ID:      "headers",
+			Type:    question.Confirm,
+			Label:   "Add SPDX headers to generated source files?",
+			Help:    "Adds SPDX-FileCopyrightText and SPDX-License-Identifier comments to the source files pcli wrote in this run that have none.",
+			Default: selected,
+		})
+	}
+	if offered, selected := p.items.Default("license_manifests", true); offered {
+		qs = append(qs, question.Question{
+			ID:      "manifests",
+			Type:    question.Confirm,
+			Label:   "Set the license field of package manifests?",
+			Help:    "package.json, pyproject.toml and Cargo.toml, when present.",
+			Default: selected,
+		})
+	}
+	return qs, nil
+}
+
+// gitUser returns git's user.name for the project, or the global one
+// without a project; "" when unset. The query changes nothing, so it runs
+// through the query runner rather than the session one, which would journal
+// it or, in a dry run, only record it.
+func (p *LicensePlugin) gitUser(projectPath string) string {
+	cmd := runner.Cmd("git", "config", "--global", "user.name")
+	if projectPath != "" {
+		cmd = runner.Cmd("git", "config", "user.name").In(projectPath)
+	}
+	out, err := p.query.Run(cmd)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// Apply writes LICENSE, keeping an existing one, then the headers and
+// manifest fields that were asked for.
+func (p *LicensePlugin) Apply(projectPath, projectType string, answers question.Answers) ([]string, error) {
+	l, ok := find(answers.String("license"))
+	if !ok {
+		return nil, fmt.Errorf("unknown license %q", answers.String("license"))
+	}
+	year, holder := answers.String("year"), answers.String("holder")
+
+	var summary []string
+
+	data, err := l.render(year, holder)
+	if err != nil {
+		return summary, fmt.Errorf("failed to render LICENSE: %w", err)
+	}
+	action, err := conflict.Write(p.fs, filepath.Join(projectPath, "LICENSE"), data, 0o644, conflict.Skip)
+	if err != nil {
+		return summary, fmt.Errorf("failed to write LICENSE: %w", err)
+	}
+	summary = append(summary, "LICENSE ("+l.ID+") "+action)
+
+	if answers.Bool("headers") {
+		n, err := p.addHeaders(projectPath, l.ID, year, holder)
+		if err != nil {
+			return summary, err
+		}
+		summary = append(summary, fmt.Sprintf("Added SPDX headers to %d file(s)", n))
+	}
+
+	if answers.Bool("manifests") {
+		updated, err := p.setManifests(projectPath, l)
+		if err != nil {
+			return summary, err
+		}
+		for _, file := range updated {
+			summary = append(summary, file+": license set")
+		}
+	}
+
+	if j := journal.Of(p.fs); j != nil {
+		j.AddPlugin("license")
+	}
+
+	return summary, nil
+}
+
+// ---------- SPDX headers ----------
+
+// commentPrefixes maps source file extensions to their line comment.
+var commentPrefixes = map[string]string{
+	".go": "//", ".rs": "//", ".js": "//", ".jsx": "//", ".ts": "//", ".tsx": "//",
+	".java": "//", ".kt": "//", ".swift": "//", ".c": "//", ".h": "//", ".cpp": "//", ".cs": "//",
+	".py": "#", ".sh": "#", ".rb": "#", ".tf": "#",
+}
+
+// addHeaders prepends the SPDX header to the generated source files
+// without one and returns how many files changed.
+func (p *LicensePlugin) addHeaders(projectPath, id, year, holder string) (int, error) {
+	n := 0
+	files := p.generatedSources(projectPath)
+	for _, path := range files {
+		data, err := p.fs.ReadFile(path)
+		if err != nil {
+			return n, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if strings.Contains(string(data), "SPDX-License-Identifier") {
+			continue
+		}
+
+		c := commentPrefixes[filepath.Ext(path)]
+		header := c + " SPDX-FileCopyrightText: " + year + " " + holder + "\n" +
+			c + " SPDX-License-Identifier: " + id + "\n\n"
+
+		if err := p.fs.WriteFile(path, withHeader(data, header), 0o644); err != nil {
+			return n, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		n++
+	}
+	return n, nil
+}
+
+// withHeader inserts header at the top of data, after a shebang line.
+func withHeader(data []byte, header string) []byte {
+	s := string(data)
+	if strings.HasPrefix(s, "#!") {
+		line, rest, _ := strings.Cut(s, "\n")
+		return []byte(line + "\n" + header + rest)
+	}
+	return []byte(header + s)
+}
+
+// generatedSources lists the source files with a known comment syntax that
+// the journal recorded as created in this run, inside projectPath. Files
+// the project had before, even ones pcli overwrote, are never touched.
+func (p *LicensePlugin) generatedSources(projectPath string) []string {
+	j := journal.Of(p.fs)
+	if j == nil {
+		return nil
+	}
+
+	seen := map[string]bool{}
+	var out []string
+	for _, path := range j.Created() {
+		rel, err := filepath.Rel(projectPath, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if seen[path] || commentPrefixes[filepath.Ext(path)] == "" {
+			continue
+		}
+		seen[path] = true
+		out = append(out, path)
+	}
+	return out
+}
+
+// ---------- Manifests ----------
+
+// setManifests writes the license field of the manifests found in the
+// project and returns the files it changed. Fields already set are kept.
+func (p *LicensePlugin) setManifests(projectPath string, l license) ([]string, error) {
+	var updated []string
+
+	npm := l.NPM
+	if npm == "" {
+		npm = l.ID
+	}
+
+	edits := []struct {
+		file string
+		edit func([]byte) ([]byte, bool, error)
+	}{
+		{"package.json", func(b []byte) ([]byte, bool, error) { return setJSONLicense(b, npm) }},
+		{"pyproject.toml", func(b []byte) ([]byte, bool, error) { return setTOMLLicense(b, "project", l.ID) }},
+		{"Cargo.toml", func(b []byte) ([]byte, bool, error) { return setTOMLLicense(b, "package", l.ID) }},
+	}
+
+	for _, e := range edits {
+		path := filepath.Join(projectPath, e.file)
+		data, err := p.fs.ReadFile(path)
+		if fsys.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return updated, fmt.Errorf("failed to read %s: %w", e.file, err)
+		}
+
+		out, changed, err := e.edit(data)
+		if err != nil {
+			return updated, fmt.Errorf("failed to update %s: %w", e.file, err)
+		}
+		if !changed {
+			continue
+		}
+		if err := p.fs.WriteFile(path, out, 0o644); err != nil {
+			return updated, fmt.Errorf("failed to write %s: %w", e.file, err)
+		}
+		updated = append(updated, e.file)
+	}
+	return updated, nil
+}
+
+// setJSONLicense adds a "license" key as the first field of the object,
+// keeping the rest of the file as written.
+func setJSONLicense(data []byte, id string) ([]byte, bool, error) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, false, err
+	}
+	if _, ok := obj["license"]; ok {
+		return data, false, nil
+	}
+
+	field, _ := json.Marshal(id)
+	s := string(data)
+	open := strings.Index(s, "{")
+	if len(obj) == 0 {
+		return []byte("{\n  \"license\": " + string(field) + "\n}\n"), true, nil
+	}
+
+	// reuse the indentation of the first key
+	rest := s[open+1:]
+	indent := "  "
+	if i := strings.Index(rest, `"`); i >= 0 {
+		line := rest[:i]
+		if nl := strings.LastIndex(line, "\n"); nl >= 0 {
+			indent = line[nl+1:]
+		}
+	}
+
+	return []byte(s[:open+1] + "\n" + indent + `"license": ` + string(field) + "," + rest), true, nil
+}
+
+// setTOMLLicense adds license = "<id>" right after the [section] header.
+func setTOMLLicense(data []byte, section, id string) ([]byte, bool, error) {
+	lines := strings.Split(string(data), "\n")
+
+	start := -1
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "["+section+"]" {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return data, false, nil
+	}
+
+	for _, line := range lines[start+1:] {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			break
+		}
+		key, _, ok := strings.Cut(trimmed, "=")
+		if ok && strings.TrimSpace(key) == "license" {
+			return data, false, nil
+		}
+	}
+
+	field := "license = " + strconv.Quote(id)
+	lines = append(lines[:start+1], append([]string{field}, lines[start+1:]...)...)
+	return []byte(strings.Join(lines, "\n")), true, nil
+}
+
+func (p *LicensePlugin) NewWizard(projectPath, projectType string) tea.Model {
+	return wizard.NewPostCreate(p, p.fs, "License", projectPath, projectType)
+}
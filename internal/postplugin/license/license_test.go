@@ -0,0 +1,95 @@
+package license
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/journal"
+	"github.com/ezeqielle/pcli/internal/question"
+	"github.com/ezeqielle/pcli/internal/runner"
+)
+
+func TestHolderDefault(t *testing.T) {
+	tests := []struct {
+		name    string
+		project string
+		script  func(*runner.Fake)
+		want    string
+	}{
+		{
+			name:    "project user",
+			project: "/src/app",
+			script:  func(f *runner.Fake) { f.On("git config user.name", "Jane Doe\n", 0) },
+			want:    "Jane Doe",
+		},
+		{
+			name:   "global user",
+			script: func(f *runner.Fake) { f.On("git config --global user.name", "Jane Doe\n", 0) },
+			want:   "Jane Doe",
+		},
+		{
+			name:    "unset",
+			project: "/src/app",
+			script:  func(f *runner.Fake) { f.On("git config user.name", "", 1) },
+			want:    "The app authors",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session, query := runner.NewFake(), runner.NewFake()
+			tt.script(query)
+
+			qs, err := New(session, query, fsys.NewMemory(), nil).Questions(tt.project, "go")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := holder(qs); got != tt.want {
+				t.Errorf("holder = %q, want %q", got, tt.want)
+			}
+			if calls := session.CommandLines(); len(calls) != 0 {
+				t.Errorf("the session runner ran %q", calls)
+			}
+		})
+	}
+}
+
+func TestHeadersOnlyOnCreatedFiles(t *testing.T) {
+	base := fsys.NewMemory()
+	if err := base.MkdirAll("/p", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := base.WriteFile("/p/old.go", []byte("package old\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fs := journal.New(base).FS()
+	for _, path := range []string{"/p/old.go", "/p/new.go", "/p/notes.txt"} {
+		if err := fs.WriteFile(path, []byte("package x\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	answers := question.Answers{"license": "MIT", "year": "2026", "holder": "Jane Doe", "headers": true}
+	if _, err := New(runner.NewFake(), runner.NewFake(), fs, nil).Apply("/p", "go", answers); err != nil {
+		t.Fatal(err)
+	}
+
+	for path, want := range map[string]bool{"/p/new.go": true, "/p/old.go": false, "/p/notes.txt": false} {
+		data, _ := base.ReadFile(path)
+		if got := strings.Contains(string(data), "SPDX-License-Identifier: MIT"); got != want {
+			t.Errorf("%s has a header: %v, want %v", path, got, want)
+		}
+	}
+}
+
+func holder(qs []question.Question) string {
+	for _, q := range qs {
+		if q.ID == "holder" {
+			s, _ := q.Default.(string)
+			return s
+		}
+	}
+	return ""
+}
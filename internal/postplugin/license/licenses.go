@@ -0,0 +1,57 @@
+package license
+
+import (
+	"embed"
+	"strings"
+)
+
+//go:embed texts
+var texts embed.FS
+
+// license is an SPDX license the plugin offers.
+type license struct {
+	// ID is the SPDX identifier written to headers and manifests.
+	ID    string
+	Label string
+	// File is the text under texts/.
+	File string
+	// Year and Holder are the placeholders of the text that take the
+	// copyright year and holder; empty when the text has none.
+	Year   string
+	Holder string
+	// NPM is the value package.json uses when it differs from ID.
+	NPM string
+}
+
+func licenses() []license {
+	return []license{
+		{ID: "MIT", Label: "MIT", File: "MIT.txt", Year: "[year]", Holder: "[fullname]"},
+		{ID: "Apache-2.0", Label: "Apache 2.0", File: "Apache-2.0.txt", Year: "[yyyy]", Holder: "[name of copyright owner]"},
+		{ID: "BSD-3-Clause", Label: "BSD 3-Clause", File: "BSD-3-Clause.txt", Year: "[year]", Holder: "[fullname]"},
+		{ID: "GPL-3.0-only", Label: "GPL 3.0", File: "GPL-3.0.txt"},
+		{ID: "MPL-2.0", Label: "MPL 2.0", File: "MPL-2.0.txt"},
+		{ID: "LicenseRef-Proprietary", Label: "Proprietary (all rights reserved)", File: "proprietary.txt", Year: "[year]", Holder: "[fullname]", NPM: "UNLICENSED"},
+	}
+}
+
+// find returns the license with the SPDX identifier id.
+func find(id string) (license, bool) {
+	for _, l := range licenses() {
+		if l.ID == id {
+			return l, true
+		}
+	}
+	return license{}, false
+}
+
+// render returns the full license text with the year and holder filled in.
+func (l license) render(year, holder string) ([]byte, error) {
+	data, err := texts.ReadFile("texts/" + l.File)
+	if err != nil {
+		return nil, err
+	}
+	if l.Year == "" {
+		return data, nil
+	}
+	return []byte(strings.NewReplacer(l.Year, year, l.Holder, holder).Replace(string(data))), nil
+}
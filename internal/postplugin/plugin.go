@@ -2,11 +2,43 @@ package postplugin
 
 import (
 	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ezeqielle/pcli/internal/pluginmeta"
+	"github.com/ezeqielle/pcli/internal/question"
 )
 
+// Plugin is a post-create step. Its wizard must send DoneMsg (see Finish)
+// when it is finished so the next plugin in the Chain can run, or AbortMsg
+// (see Abort) once it has rolled the run back.
 type Plugin interface {
 	ID() string
 	DisplayName() string
+	Metadata() pluginmeta.Metadata
 
 	NewWizard(projectPath, projectType string) tea.Model
 }
+
+// ItemStatus tells whether one of a plugin's items already exists in a project.
+type ItemStatus struct {
+	ID      string
+	Label   string
+	Path    string
+	Present bool
+}
+
+// Inspector is implemented by plugins that can report which of their items
+// are already present in an existing project.
+type Inspector interface {
+	Inspect(projectPath, projectType string) []ItemStatus
+}
+
+// Declarative is implemented by plugins that describe their questions
+// declaratively, so they can also run without a terminal UI.
+type Declarative interface {
+	// Questions may read the project to pick defaults. projectPath is empty
+	// when the project does not exist yet (pcli questions without --dir);
+	// Questions must then not touch the working directory.
+	Questions(projectPath, projectType string) ([]question.Question, error)
+	// Apply applies resolved answers to the project and describes what it did.
+	Apply(projectPath, projectType string, answers question.Answers) ([]string, error)
+}
@@ -1,9 +0,0 @@
-package postplugin
-
-import (
-	global "github.com/ezeqielle/pcli/internal/postplugin/global"
-)
-
-func RegisterAll() {
-	Register(global.New())
-}
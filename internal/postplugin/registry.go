@@ -2,17 +2,59 @@ package postplugin
 
 import (
 	"sync"
+
+	"github.com/ezeqielle/pcli/internal/pluginmeta"
+	"github.com/ezeqielle/pcli/internal/settings"
 )
 
 var (
-	mu      sync.RWMutex
-	plugins []Plugin
+	mu        sync.RWMutex
+	plugins   []Plugin
+	selection settings.Selection
 )
 
-func Register(p Plugin) {
+// Configure sets which registered plugins are offered and in what order.
+func Configure(s settings.Selection) {
 	mu.Lock()
 	defer mu.Unlock()
+
+	selection = s
+}
+
+// selected filters and reorders ps with the configured selection.
+func selected(ps []Plugin) []Plugin {
+	mu.RLock()
+	s := selection
+	mu.RUnlock()
+
+	ids := make([]string, len(ps))
+	for i, p := range ps {
+		ids[i] = p.ID()
+	}
+
+	out := make([]Plugin, 0, len(ps))
+	for _, i := range s.Apply(ids) {
+		out = append(out, ps[i])
+	}
+	return out
+}
+
+// Register adds p to the registry. It rejects invalid or duplicate IDs and
+// plugins that conflict with an already registered one.
+func Register(p Plugin) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	registered := make([]pluginmeta.Node, len(plugins))
+	for i, r := range plugins {
+		registered[i] = pluginmeta.Node{ID: r.ID(), Meta: r.Metadata()}
+	}
+	if err := pluginmeta.CheckRegistration(pluginmeta.Node{ID: p.ID(), Meta: p.Metadata()}, registered); err != nil {
+		return err
+	}
+
 	plugins = append(plugins, p)
+	return nil
 }
 
 func All() []Plugin {
@@ -23,3 +65,43 @@ func All() []Plugin {
 	copy(out, plugins)
 	return out
 }
+
+// Enabled returns the plugins the configuration allows, in its order.
+func Enabled() []Plugin {
+	return selected(All())
+}
+
+// For returns the enabled plugins supporting projectType, ordered by their
+// After/Before hints unless the configuration sets an explicit order.
+func For(projectType string) []Plugin {
+	var supported []Plugin
+	for _, p := range All() {
+		if p.Metadata().Supports(projectType) {
+			supported = append(supported, p)
+		}
+	}
+
+	nodes := make([]pluginmeta.Node, len(supported))
+	for i, p := range supported {
+		nodes[i] = pluginmeta.Node{ID: p.ID(), Meta: p.Metadata()}
+	}
+
+	out := make([]Plugin, 0, len(supported))
+	for _, i := range pluginmeta.Order(nodes) {
+		out = append(out, supported[i])
+	}
+	return selected(out)
+}
+
+// Get returns the registered plugin with the given ID.
+func Get(id string) (Plugin, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for _, p := range plugins {
+		if p.ID() == id {
+			return p, true
+		}
+	}
+	return nil, false
+}
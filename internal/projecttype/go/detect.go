@@ -0,0 +1,42 @@
+package goproject
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/ezeqielle/pcli/internal/gomod"
+)
+
+// Detect recognises a Go module by its go.mod, or a loose Go project by its
+// .go files.
+func (p *GoPlugin) Detect(dir string) (float64, map[string]string) {
+	data, err := p.fs.ReadFile(filepath.Join(dir, "go.mod"))
+	if err == nil {
+		mod := gomod.Parse(data)
+		meta := map[string]string{}
+		if mod.Module != "" {
+			meta["module"] = mod.Module
+		}
+		if mod.GoVersion != "" {
+			meta["go"] = mod.GoVersion
+		}
+		if mod.Toolchain != "" {
+			meta["toolchain"] = mod.Toolchain
+		}
+		if mod.Module == "" {
+			return 0.8, meta
+		}
+		return 1, meta
+	}
+
+	entries, err := p.fs.ReadDir(dir)
+	if err != nil {
+		return 0, nil
+	}
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".go") {
+			return 0.4, map[string]string{}
+		}
+	}
+	return 0, nil
+}
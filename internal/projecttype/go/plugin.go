@@ -1,24 +1,37 @@
 package goproject
 
 import (
+	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/progress"
-	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/ezeqielle/pcli/internal/dotenv"
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/journal"
 	"github.com/ezeqielle/pcli/internal/langenv"
+	"github.com/ezeqielle/pcli/internal/nav"
+	"github.com/ezeqielle/pcli/internal/plan"
+	"github.com/ezeqielle/pcli/internal/pluginmeta"
 	"github.com/ezeqielle/pcli/internal/postplugin"
+	"github.com/ezeqielle/pcli/internal/question"
+	"github.com/ezeqielle/pcli/internal/runner"
+	"github.com/ezeqielle/pcli/internal/ui"
+	"github.com/ezeqielle/pcli/internal/version"
+	"github.com/ezeqielle/pcli/internal/wizard"
 )
 
-type GoPlugin struct{}
+type GoPlugin struct {
+	runner runner.Runner
+	fs     fsys.FS
+}
 
-func New() *GoPlugin {
-	return &GoPlugin{}
+func New(r runner.Runner, fs fsys.FS) *GoPlugin {
+	return &GoPlugin{runner: r, fs: fs}
 }
 
 func (p *GoPlugin) ID() string {
@@ -33,8 +46,57 @@ func (p *GoPlugin) Description() string {
 	return "Create a Go project using module path workflow"
 }
 
+func (p *GoPlugin) Metadata() pluginmeta.Metadata {
+	return pluginmeta.Metadata{
+		Version:       version.Version,
+		Author:        "pcli",
+		Tags:          []string{"language", "go", "module"},
+		Category:      "language",
+		RequiredTools: []string{"go"},
+	}
+}
+
+// Questions declares the Go wizard's inputs.
+func (p *GoPlugin) Questions() ([]question.Question, error) {
+	return goQuestions()
+}
+
+// Create creates the project without a UI. Go must already be installed.
+func (p *GoPlugin) Create(answers question.Answers) (string, error) {
+	if !langenv.IsInstalled(p.runner, langenv.LanguageGo) {
+		return "", errors.New("go is not installed; install it or run pcli interactively to install it")
+	}
+
+	modulePath := answers.String("module_path")
+	dir, err := createGoProject(p.runner, p.fs, modulePath)
+	if err != nil {
+		return dir, err
+	}
+
+	if j := journal.Of(p.fs); j != nil {
+		j.SetProject("go", modulePath, dir)
+	}
+	return dir, nil
+}
+
+// goQuestions asks for the module path, offering the default from .env.
+// A malformed .env is reported along with the questions.
+func goQuestions() ([]question.Question, error) {
+	defaultModule, err := loadDefaultModulePath()
+
+	return []question.Question{{
+		ID:       "module_path",
+		Type:     question.Text,
+		Label:    "Go project – module path",
+		Help:     "The project folder is named after the last path element.",
+		Default:  defaultModule,
+		Required: true,
+		Check:    validateModulePath,
+	}}, err
+}
+
 func (p *GoPlugin) NewWizard() tea.Model {
-	return NewGoWizardModel()
+	return NewGoWizardModel(p.runner, p.fs)
 }
 
 // -------------------------------------------
@@ -48,6 +110,7 @@ const (
 	goStepSummary
 	goStepInstallPrompt
 	goStepInstalling
+	goStepRollbackPrompt
 	goStepDone
 )
 
@@ -62,43 +125,75 @@ type installFinishedMsg struct {
 type GoWizardModel struct {
 	step goWizardStep
 
+	runner runner.Runner
+	fs     fsys.FS
+
 	modulePath string
 	projectDir string
 	errMsg     string
 
-	modulePathInput textinput.Model
+	// planPreview is the rendered dry-run of createGoProject shown in the summary.
+	planPreview string
+
+	// chain runs the post-create plugins on the created project; it is
+	// built once, when creation succeeds.
+	chain postplugin.Chain
+
+	// failure is the creation error shown while offering a rollback.
+	failure  string
+	rollback wizard.Rollback
+
+	form          wizard.Form
+	installPrompt ui.Confirm
 
 	progress      progress.Model
 	progressValue float64
 
 	installEvents chan tea.Msg
 
-	logLines []string
+	logs ui.LogView
 }
 
-func NewGoWizardModel() GoWizardModel {
-	defaultModule := loadDefaultModulePath()
-
-	ti := textinput.New()
-	ti.Placeholder = defaultModule
-	ti.SetValue(defaultModule)
-	ti.Focus()
+// installChrome is the number of lines the install screen shows around
+// the log viewport.
+const installChrome = 9
 
+func NewGoWizardModel(r runner.Runner, fs fsys.FS) GoWizardModel {
 	prog := progress.New(progress.WithDefaultGradient())
 	prog.Width = 40
 
+	qs, err := goQuestions()
+	title := ""
+	if err != nil {
+		title = ui.Styles().Warn.Render("Warning: "+err.Error()) + "\n"
+	}
+
 	return GoWizardModel{
-		step:            goStepModulePath,
-		modulePathInput: ti,
-		progress:        prog,
-		logLines:        make([]string, 0, 64),
+		step:          goStepModulePath,
+		runner:        r,
+		fs:            fs,
+		form:          wizard.NewForm(title, qs, nil),
+		installPrompt: ui.NewConfirm("Do you want to install Go now?", true),
+		progress:      prog,
+		logs:          ui.NewLogView(80, 12),
 	}
 }
 
-func (m GoWizardModel) Init() tea.Cmd {
+func validateModulePath(v any) error {
+	modulePath, _ := v.(string)
+	if modulePath == "" {
+		return errors.New("module path cannot be empty")
+	}
+	if strings.ContainsAny(modulePath, " \t") {
+		return errors.New("module path cannot contain spaces")
+	}
 	return nil
 }
 
+func (m GoWizardModel) Init() tea.Cmd {
+	return m.form.Init()
+}
+
 func (m GoWizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
@@ -111,57 +206,62 @@ func (m GoWizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.logs.SetSize(msg.Width, max(msg.Height-installChrome, 3))
+		m.progress.Width = min(max(msg.Width-4, 10), 60)
+		if m.step != goStepModulePath {
+			// the form gets every message on its own step below
+			m.form, _ = m.form.Update(msg)
+		}
+
 	case tea.KeyMsg:
 		switch m.step {
 
 		case goStepModulePath:
-			switch msg.String() {
-			case "enter":
-				m.modulePath = strings.TrimSpace(m.modulePathInput.Value())
-				if m.modulePath == "" {
-					m.errMsg = "module path cannot be empty"
-					return m, tea.Batch(cmds...)
-				}
-				m.projectDir = previewProjectDir(m.modulePath)
-				m.errMsg = ""
-				m.step = goStepSummary
-				return m, tea.Batch(cmds...)
-
-			case "ctrl+c":
+			if msg.String() == "ctrl+c" {
 				return m, tea.Quit
 			}
 
 		case goStepSummary:
 			switch msg.String() {
 			case "enter":
-				if !langenv.IsInstalled(langenv.LanguageGo) {
+				if !langenv.IsInstalled(m.runner, langenv.LanguageGo) {
 					m.step = goStepInstallPrompt
 					m.errMsg = ""
 					return m, tea.Batch(cmds...)
 				}
 
-				dir, err := createGoProject(m.modulePath)
+				dir, err := createGoProject(m.runner, m.fs, m.modulePath)
 				if err != nil {
-					m.errMsg = err.Error()
 					m.projectDir = dir
+					if wizard.CanRollback(m.fs) {
+						m.failure = err.Error()
+						m.rollback = wizard.NewRollback(m.fs, "Go project", "Project creation failed:\n\n"+m.failure)
+						m.step = goStepRollbackPrompt
+						return m, tea.Batch(cmds...)
+					}
+					m.errMsg = err.Error()
 					return m, tea.Batch(cmds...)
 				}
 				m.projectDir = dir
 				m.errMsg = ""
+				m.chain = postplugin.NewChainFor(dir, "go")
 
-				// After project creation, hand off to the first post-create plugin
-				postPlugins := postplugin.All()
-				if len(postPlugins) > 0 {
-					return postPlugins[0].NewWizard(m.projectDir, "go"), nil
+				if j := journal.Of(m.fs); j != nil {
+					j.SetProject("go", m.modulePath, dir)
 				}
 
-				// If no post-create plugin is registered, fall back to the simple done screen
+				// After project creation, hand off to the post-create plugins;
+				// going back from them lands on the done screen
 				m.step = goStepDone
-				return m, tea.Batch(cmds...)
+				return m, tea.Batch(append(cmds, m.postCreate())...)
 
 			case "esc":
 				m.step = goStepModulePath
 				m.errMsg = ""
+				var fCmd tea.Cmd
+				m.form, fCmd = m.form.Reopen()
+				cmds = append(cmds, fCmd)
 				return m, tea.Batch(cmds...)
 
 			case "ctrl+c":
@@ -169,9 +269,9 @@ func (m GoWizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		case goStepInstallPrompt:
-			switch msg.String() {
-			case "y", "Y", "enter":
-				cmd, err := langenv.InstallCommand(langenv.LanguageGo)
+			yes, answered := m.installPrompt.Answer(msg)
+			if answered && yes {
+				cmd, err := langenv.InstallCommand(m.runner, m.fs, langenv.LanguageGo)
 				if err != nil {
 					m.errMsg = err.Error()
 					m.step = goStepSummary
@@ -182,37 +282,69 @@ func (m GoWizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.errMsg = ""
 				m.progressValue = 0.0
 				m.progress.SetPercent(0.0)
-				m.logLines = nil
+				m.logs.Reset()
 
 				m.installEvents = make(chan tea.Msg)
-				go runInstallWithOutput(cmd, m.installEvents)
+				go runInstallWithOutput(m.runner, cmd, m.installEvents)
 
 				cmds = append(cmds, waitInstallEvent(m.installEvents))
 				return m, tea.Batch(cmds...)
-
-			case "n", "N", "esc":
+			}
+			if answered || msg.String() == "esc" {
 				m.errMsg = "Go is required to create a Go project. Please install it and retry."
 				m.step = goStepSummary
 				return m, tea.Batch(cmds...)
-
-			case "ctrl+c":
+			}
+			if msg.String() == "ctrl+c" {
 				return m, tea.Quit
 			}
+			m.installPrompt, _ = m.installPrompt.Update(msg)
 
 		case goStepInstalling:
-			switch msg.String() {
-			case "ctrl+c":
+			if msg.String() == "ctrl+c" {
 				return m, tea.Quit
 			}
+			var lCmd tea.Cmd
+			m.logs, lCmd = m.logs.Update(msg)
+			cmds = append(cmds, lCmd)
+
+		case goStepRollbackPrompt:
+			var rCmd tea.Cmd
+			m.rollback, rCmd = m.rollback.Update(msg)
+			cmds = append(cmds, rCmd)
+
+			switch m.rollback.State() {
+			case wizard.RolledBack:
+				m.errMsg = m.failure + "\n" + strings.Join(m.rollback.Summary(), "\n")
+				if err := m.rollback.Err(); err != nil {
+					m.errMsg = fmt.Sprintf("%s\nRollback incomplete: %v", m.failure, err)
+				}
+				m.failure = ""
+				m.step = goStepSummary
+
+			case wizard.Kept:
+				m.errMsg = m.failure
+				m.failure = ""
+				m.step = goStepSummary
+			}
+			return m, tea.Batch(cmds...)
 
 		case goStepDone:
-			// any key exits
+			switch msg.String() {
+			case "esc":
+				return m, nav.Back
+			case "enter":
+				if cmd := m.postCreate(); cmd != nil {
+					return m, cmd
+				}
+			}
+			// any other key exits
 			return m, tea.Quit
 		}
 
 	case installLogMsg:
 		if m.step == goStepInstalling {
-			m.appendLogLine(msg.Line)
+			m.logs.Append(msg.Line)
 
 			// bump progress a bit per line
 			m.progressValue += 0.02
@@ -240,85 +372,110 @@ func (m GoWizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
-	// Update text input in module path step
+	// The module path step is the declarative form
 	if m.step == goStepModulePath {
-		var tiCmd tea.Cmd
-		m.modulePathInput, tiCmd = m.modulePathInput.Update(msg)
-		if tiCmd != nil {
-			cmds = append(cmds, tiCmd)
+		var fCmd tea.Cmd
+		m.form, fCmd = m.form.Update(msg)
+		cmds = append(cmds, fCmd)
+
+		switch m.form.State() {
+		case wizard.Submitted:
+			m.modulePath = m.form.Answers().String("module_path")
+			m.projectDir = previewProjectDir(m.modulePath)
+			m.planPreview = m.previewPlan()
+			m.errMsg = ""
+			m.step = goStepSummary
+
+		case wizard.Cancelled:
+			// back to the type chooser; the answer is kept for coming back
+			m.form = m.form.Resume()
+			cmds = append(cmds, nav.Back)
 		}
 	}
 
 	return m, tea.Batch(cmds...)
 }
 
-func (m *GoWizardModel) appendLogLine(line string) {
-	if line == "" {
-		return
+// postCreate pushes the post-create plugins for the created project, or
+// returns nil when none is registered.
+func (m GoWizardModel) postCreate() tea.Cmd {
+	if m.chain.Empty() {
+		return nil
 	}
-	m.logLines = append(m.logLines, line)
-	const maxLines = 50
-	if len(m.logLines) > maxLines {
-		m.logLines = m.logLines[len(m.logLines)-maxLines:]
+	return nav.Push("post-create:"+m.projectDir, "Post-create", m.chain)
+}
+
+// previewPlan records what createGoProject would do without touching disk.
+func (m GoWizardModel) previewPlan() string {
+	p := plan.New()
+	r := runner.NewDryRun(m.runner, p)
+	fs := fsys.NewDryRun(m.fs, p)
+
+	if _, err := createGoProject(r, fs, m.modulePath); err != nil {
+		return ""
 	}
+	return p.Render()
 }
 
 func (m GoWizardModel) View() string {
 	switch m.step {
 
 	case goStepModulePath:
-		var errLine string
-		if m.errMsg != "" {
-			errLine = "\n\nError: " + m.errMsg
-		}
-
-		return "Go project – module path\n\n" +
-			m.modulePathInput.View() + "\n\n" +
-			"[enter] Continue   [ctrl+c] Quit" +
-			errLine + "\n"
+		return m.form.View()
 
 	case goStepSummary:
 		var b strings.Builder
 
-		b.WriteString("Summary – Go project\n\n")
+		b.WriteString(ui.Header("Summary – Go project", 0, 0))
 		b.WriteString(fmt.Sprintf("Module path:  %s\n", m.modulePath))
 		b.WriteString(fmt.Sprintf("Project path: %s\n\n", previewProjectDir(m.modulePath)))
 
+		if m.planPreview != "" {
+			if _, dryRun := fsys.PlanOf(m.fs); dryRun {
+				b.WriteString("Plan (dry run, nothing will be written):\n")
+			} else {
+				b.WriteString("Plan:\n")
+			}
+			b.WriteString(m.planPreview + "\n")
+		}
+
 		if m.errMsg != "" {
-			b.WriteString("Info: " + m.errMsg + "\n\n")
+			b.WriteString(ui.InfoLine(m.errMsg) + "\n")
 		}
 
-		b.WriteString("[enter] Create   [esc] Back   [ctrl+c] Quit\n")
+		b.WriteString(ui.Help("[enter] Create", "[esc] Back", "[ctrl+c] Quit"))
 
 		return b.String()
 
 	case goStepInstallPrompt:
 		return "Go is not installed on this system.\n\n" +
-			"Do you want to install Go now?\n\n" +
-			"[y] Yes   [n] No   [ctrl+c] Quit\n"
+			m.installPrompt.View() +
+			ui.Help(ui.ConfirmKeys, "[ctrl+c] Quit")
 
 	case goStepInstalling:
 		var b strings.Builder
 
 		b.WriteString("Installing Go...\n\n")
 		b.WriteString(m.progress.View())
-		b.WriteString("\n\nLogs (latest):\n")
-
-		for _, line := range m.logLines {
-			b.WriteString(line)
-			b.WriteString("\n")
-		}
-
-		b.WriteString("\n[ctrl+c] Cancel\n")
+		b.WriteString("\n\nLogs:\n")
+		b.WriteString(m.logs.View())
+		b.WriteString(ui.Help(ui.LogViewKeys, "[ctrl+c] Cancel"))
 
 		return b.String()
 
+	case goStepRollbackPrompt:
+		return m.rollback.View()
+
 	case goStepDone:
-		return fmt.Sprintf(
-			"Go project created.\n\nModule path:  %s\nProject path: %s\n\n[any key] Exit\n",
+		help := ui.Help("[esc] Back", "[any key] Exit")
+		if len(postplugin.For("go")) > 0 {
+			help = ui.Help("[enter] Post-create steps", "[esc] Back", "[any key] Exit")
+		}
+		return ui.Header("Go project created", 0, 0) + fmt.Sprintf(
+			"Module path:  %s\nProject path: %s\n",
 			m.modulePath,
 			m.projectDir,
-		)
+		) + help
 	}
 
 	return ""
@@ -328,13 +485,15 @@ func (m GoWizardModel) View() string {
 // Install streaming helpers
 // -------------------------------------------
 
-func runInstallWithOutput(cmd *exec.Cmd, ch chan<- tea.Msg) {
+func runInstallWithOutput(r runner.Runner, cmd runner.Command, ch chan<- tea.Msg) {
 	defer close(ch)
 
 	lines := make(chan string)
+	done := make(chan struct{})
 
 	// Reader goroutine: transform lines into tea.Msg
 	go func() {
+		defer close(done)
 		for line := range lines {
 			ch <- installLogMsg{Line: line}
 			ch <- installProgressMsg{}
@@ -342,9 +501,11 @@ func runInstallWithOutput(cmd *exec.Cmd, ch chan<- tea.Msg) {
 	}()
 
 	// Blocking call – runs command and streams output to `lines`
-	err := langenv.RunWithOutput(cmd, lines)
+	err := r.Stream(cmd, lines)
 	// Done with output, close lines so reader goroutine stops
 	close(lines)
+	// Wait for the last lines to be forwarded before reporting the end
+	<-done
 
 	ch <- installFinishedMsg{Err: err}
 }
@@ -363,47 +524,42 @@ func waitInstallEvent(ch <-chan tea.Msg) tea.Cmd {
 // Env, paths, project creation
 // -------------------------------------------
 
-func loadDefaultModulePath() string {
-	data, err := os.ReadFile(".env")
+// fallbackModulePath is offered when .env sets no default module path.
+const fallbackModulePath = "github.com/you/your-service"
+
+// loadEnv reads pcli's .env from the working directory; a missing file
+// sets nothing.
+func loadEnv() (map[string]string, error) {
+	return dotenv.ReadFile(fsys.NewOS(), ".env")
+}
+
+func loadDefaultModulePath() (string, error) {
+	env, err := loadEnv()
 	if err != nil {
-		return "github.com/you/your-service"
+		return fallbackModulePath, err
 	}
 
-	lines := strings.Split(string(data), "\n")
-	for _, line := range lines {
-		if strings.HasPrefix(line, "DEFAULT_GO_PROJECT_MODULE_PATH=") {
-			val := strings.TrimPrefix(line, "DEFAULT_GO_PROJECT_MODULE_PATH=")
-			val = strings.TrimSpace(val)
-			if val != "" {
-				return langenv.ExpandPathEnv(val)
-			}
-		}
+	if val := strings.TrimSpace(env["DEFAULT_GO_PROJECT_MODULE_PATH"]); val != "" {
+		return langenv.ExpandPathEnv(val), nil
 	}
-
-	return "github.com/you/your-service"
+	return fallbackModulePath, nil
 }
 
-func loadDefaultProjectBasePath() string {
-	data, err := os.ReadFile(".env")
-	if err == nil {
-		lines := strings.Split(string(data), "\n")
-		for _, line := range lines {
-			if strings.HasPrefix(line, "DEFAULT_GO_PROJECT_PATH=") {
-				val := strings.TrimPrefix(line, "DEFAULT_GO_PROJECT_PATH=")
-				val = strings.TrimSpace(val)
-				if val != "" {
-					return langenv.ExpandPathEnv(val)
-				}
-			}
-		}
+// loadDefaultProjectBasePath returns DEFAULT_GO_PROJECT_PATH from .env,
+// or ~/Documents. With a malformed .env it returns the fallback and the
+// error.
+func loadDefaultProjectBasePath() (string, error) {
+	env, err := loadEnv()
+	if val := strings.TrimSpace(env["DEFAULT_GO_PROJECT_PATH"]); err == nil && val != "" {
+		return langenv.ExpandPathEnv(val), nil
 	}
 
-	home, err := os.UserHomeDir()
-	if err == nil && home != "" {
-		return filepath.Join(home, "Documents")
+	home, homeErr := os.UserHomeDir()
+	if homeErr == nil && home != "" {
+		return filepath.Join(home, "Documents"), err
 	}
 
-	return "."
+	return ".", err
 }
 
 func deriveProjectNameFromModule(modulePath string) string {
@@ -416,36 +572,38 @@ func deriveProjectNameFromModule(modulePath string) string {
 }
 
 func previewProjectDir(modulePath string) string {
-	base := loadDefaultProjectBasePath()
+	// createGoProject reports a malformed .env
+	base, _ := loadDefaultProjectBasePath()
 	name := deriveProjectNameFromModule(modulePath)
 	return filepath.Join(base, name)
 }
 
-func createGoProject(modulePath string) (string, error) {
+func createGoProject(r runner.Runner, fs fsys.FS, modulePath string) (string, error) {
 	modulePath = strings.TrimSpace(modulePath)
 	if modulePath == "" {
 		return "", fmt.Errorf("module path cannot be empty")
 	}
 
-	base := loadDefaultProjectBasePath()
+	base, err := loadDefaultProjectBasePath()
+	if err != nil {
+		return "", err
+	}
 	name := deriveProjectNameFromModule(modulePath)
 	projectDir := filepath.Join(base, name)
 
-	if err := os.MkdirAll(projectDir, 0o755); err != nil {
+	if err := fs.MkdirAll(projectDir, 0o755); err != nil {
 		return projectDir, fmt.Errorf("failed to create project directory: %w", err)
 	}
 
-	modInit := exec.Command("go", "mod", "init", modulePath)
-	modInit.Dir = projectDir
+	modInit := runner.Cmd("go", "mod", "init", modulePath).In(projectDir)
 
-	if out, err := modInit.CombinedOutput(); err != nil {
+	if out, err := r.Run(modInit); err != nil {
 		return projectDir, fmt.Errorf("go mod init failed: %v\n%s", err, string(out))
 	}
 
-	modTidy := exec.Command("go", "mod", "tidy")
-	modTidy.Dir = projectDir
+	modTidy := runner.Cmd("go", "mod", "tidy").In(projectDir)
 
-	if out, err := modTidy.CombinedOutput(); err != nil {
+	if out, err := r.Run(modTidy); err != nil {
 		return projectDir, fmt.Errorf("go mod tidy failed: %v\n%s", err, string(out))
 	}
 
@@ -0,0 +1,206 @@
+package goproject
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/journal"
+	"github.com/ezeqielle/pcli/internal/question"
+	"github.com/ezeqielle/pcli/internal/runner"
+)
+
+// withProjectBase makes .env point new Go projects at a temporary
+// directory and returns it.
+func withProjectBase(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	base := filepath.Join(dir, "work")
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("DEFAULT_GO_PROJECT_PATH="+base+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+	return base
+}
+
+func key(s string) tea.KeyMsg {
+	if s == "enter" {
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestCreateGoProject(t *testing.T) {
+	base := withProjectBase(t)
+	r := runner.NewFake()
+	fs := fsys.NewMemory()
+
+	dir, err := createGoProject(r, fs, " example.com/acme/svc ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.Join(base, "svc")
+	if dir != want {
+		t.Errorf("dir = %q, want %q", dir, want)
+	}
+	if info, err := fs.Stat(want); err != nil || !info.IsDir() {
+		t.Errorf("project directory not created: %v", err)
+	}
+
+	calls := []runner.Command{
+		runner.Cmd("go", "mod", "init", "example.com/acme/svc").In(want),
+		runner.Cmd("go", "mod", "tidy").In(want),
+	}
+	if got := r.Calls(); !reflect.DeepEqual(got, calls) {
+		t.Errorf("calls = %v, want %v", got, calls)
+	}
+}
+
+func TestCreateGoProjectFailures(t *testing.T) {
+	base := withProjectBase(t)
+
+	tests := []struct {
+		name  string
+		fail  string
+		want  string
+		calls int
+	}{
+		{"mod init", "go mod init *", "go mod init failed", 1},
+		{"mod tidy", "go mod tidy", "go mod tidy failed", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := runner.NewFake().On(tt.fail, "boom", 1)
+
+			dir, err := createGoProject(r, fsys.NewMemory(), "example.com/svc")
+			if err == nil || !strings.Contains(err.Error(), tt.want) || !strings.Contains(err.Error(), "boom") {
+				t.Errorf("error = %v, want %q with the command output", err, tt.want)
+			}
+			if want := filepath.Join(base, "svc"); dir != want {
+				t.Errorf("dir = %q, want %q", dir, want)
+			}
+			if n := len(r.Calls()); n != tt.calls {
+				t.Errorf("ran %d commands, want %d", n, tt.calls)
+			}
+		})
+	}
+
+	if _, err := createGoProject(runner.NewFake(), fsys.NewMemory(), "  "); err == nil {
+		t.Error("createGoProject accepted an empty module path")
+	}
+}
+
+func TestCreate(t *testing.T) {
+	base := withProjectBase(t)
+
+	r := runner.NewFake()
+	if _, err := New(r, fsys.NewMemory()).Create(question.Answers{"module_path": "example.com/svc"}); err == nil {
+		t.Error("Create succeeded without go installed")
+	}
+	if calls := r.Calls(); len(calls) != 0 {
+		t.Errorf("Create without go ran %v", calls)
+	}
+
+	j := journal.New(fsys.NewMemory())
+	r.WithPath("go", "/usr/bin/go")
+	dir, err := New(j.Runner(r), j.FS()).Create(question.Answers{"module_path": "example.com/svc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(base, "svc"); dir != want {
+		t.Errorf("dir = %q, want %q", dir, want)
+	}
+	info := j.Info()
+	if info.ProjectType != "go" || info.Module != "example.com/svc" || info.Dir != dir {
+		t.Errorf("journal info = %+v", info)
+	}
+	if !j.HasChanges() {
+		t.Error("the project directory was not journaled")
+	}
+}
+
+func TestWizardInstallPrompt(t *testing.T) {
+	withProjectBase(t)
+
+	r := runner.NewFake()
+	m := NewGoWizardModel(r, fsys.NewMemory())
+	m.step = goStepSummary
+	m.modulePath = "example.com/svc"
+
+	next, _ := m.Update(key("enter"))
+	m = next.(GoWizardModel)
+	if m.step != goStepInstallPrompt {
+		t.Fatalf("step = %v, want the install prompt", m.step)
+	}
+	if calls := r.Calls(); len(calls) != 0 {
+		t.Errorf("ran %v before go was installed", calls)
+	}
+
+	next, _ = m.Update(key("n"))
+	m = next.(GoWizardModel)
+	if m.step != goStepSummary || !strings.Contains(m.errMsg, "Go is required") {
+		t.Errorf("declining: step = %v, errMsg = %q", m.step, m.errMsg)
+	}
+}
+
+func TestWizardRollsBackFailedCreate(t *testing.T) {
+	base := withProjectBase(t)
+
+	mem := fsys.NewMemory()
+	j := journal.New(mem)
+	r := runner.NewFake().WithPath("go", "/usr/bin/go").On("go mod tidy", "no network", 1)
+
+	m := NewGoWizardModel(j.Runner(r), j.FS())
+	m.step = goStepSummary
+	m.modulePath = "example.com/svc"
+
+	next, _ := m.Update(key("enter"))
+	m = next.(GoWizardModel)
+	if m.step != goStepRollbackPrompt || !strings.Contains(m.failure, "go mod tidy failed") {
+		t.Fatalf("step = %v, failure = %q; want the rollback prompt", m.step, m.failure)
+	}
+
+	next, _ = m.Update(key("y"))
+	m = next.(GoWizardModel)
+	if m.step != goStepSummary || !strings.Contains(m.errMsg, "Rolled back") {
+		t.Errorf("after rollback: step = %v, errMsg = %q", m.step, m.errMsg)
+	}
+	if fsys.Exists(mem, base) {
+		t.Errorf("rollback left %v", mem.Paths())
+	}
+}
+
+func TestRunInstallWithOutput(t *testing.T) {
+	cmd := runner.Cmd("sh", "-c", "install go")
+	r := runner.NewFake().On(cmd.String(), "fetching\ninstalling\n", 2)
+
+	ch := make(chan tea.Msg)
+	go runInstallWithOutput(r, cmd, ch)
+
+	var lines []string
+	var finished *installFinishedMsg
+	for msg := range ch {
+		switch msg := msg.(type) {
+		case installLogMsg:
+			lines = append(lines, msg.Line)
+		case installFinishedMsg:
+			finished = &msg
+		}
+	}
+
+	if want := []string{"fetching", "installing"}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("lines = %q, want %q", lines, want)
+	}
+	var exit *runner.ExitError
+	if finished == nil || !errors.As(finished.Err, &exit) || exit.Code != 2 {
+		t.Errorf("finished = %+v, want exit status 2", finished)
+	}
+}
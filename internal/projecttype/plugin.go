@@ -2,12 +2,32 @@ package projecttype
 
 import (
 	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ezeqielle/pcli/internal/pluginmeta"
+	"github.com/ezeqielle/pcli/internal/question"
 )
 
 type Plugin interface {
 	ID() string
 	DisplayName() string
 	Description() string
+	Metadata() pluginmeta.Metadata
 
 	NewWizard() tea.Model
 }
+
+// Detector is implemented by plugins that can recognise a project they could
+// have created. Confidence ranges from 0 (not this type) to 1 (certain);
+// metadata carries what was learned, e.g. the module path or toolchain version.
+type Detector interface {
+	Detect(dir string) (confidence float64, metadata map[string]string)
+}
+
+// Declarative is implemented by plugins that describe their questions
+// declaratively. The same definition drives the interactive wizard and
+// non-interactive runs from flags or an answers file.
+type Declarative interface {
+	Questions() ([]question.Question, error)
+	// Create creates the project from resolved answers and returns its directory.
+	Create(answers question.Answers) (string, error)
+}
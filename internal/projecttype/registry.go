@@ -2,17 +2,59 @@ package projecttype
 
 import (
 	"sync"
+
+	"github.com/ezeqielle/pcli/internal/pluginmeta"
+	"github.com/ezeqielle/pcli/internal/settings"
 )
 
 var (
-	mu      sync.RWMutex
-	plugins []Plugin
+	mu        sync.RWMutex
+	plugins   []Plugin
+	selection settings.Selection
 )
 
-func Register(p Plugin) {
+// Configure sets which registered plugins are offered and in what order.
+func Configure(s settings.Selection) {
 	mu.Lock()
 	defer mu.Unlock()
+
+	selection = s
+}
+
+// selected filters and reorders ps with the configured selection.
+func selected(ps []Plugin) []Plugin {
+	mu.RLock()
+	s := selection
+	mu.RUnlock()
+
+	ids := make([]string, len(ps))
+	for i, p := range ps {
+		ids[i] = p.ID()
+	}
+
+	out := make([]Plugin, 0, len(ps))
+	for _, i := range s.Apply(ids) {
+		out = append(out, ps[i])
+	}
+	return out
+}
+
+// Register adds p to the registry. It rejects invalid or duplicate IDs and
+// plugins that conflict with an already registered one.
+func Register(p Plugin) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	registered := make([]pluginmeta.Node, len(plugins))
+	for i, r := range plugins {
+		registered[i] = pluginmeta.Node{ID: r.ID(), Meta: r.Metadata()}
+	}
+	if err := pluginmeta.CheckRegistration(pluginmeta.Node{ID: p.ID(), Meta: p.Metadata()}, registered); err != nil {
+		return err
+	}
+
 	plugins = append(plugins, p)
+	return nil
 }
 
 func All() []Plugin {
@@ -23,3 +65,21 @@ func All() []Plugin {
 	copy(out, plugins)
 	return out
 }
+
+// Enabled returns the plugins the configuration offers, in its order.
+func Enabled() []Plugin {
+	return selected(All())
+}
+
+// Get returns the registered plugin with the given ID.
+func Get(id string) (Plugin, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for _, p := range plugins {
+		if p.ID() == id {
+			return p, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,314 @@
+package question
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Type is the kind of input a question asks for.
+type Type string
+
+const (
+	Text        Type = "text"
+	Select      Type = "select"
+	MultiSelect Type = "multiselect"
+	Confirm     Type = "confirm"
+)
+
+// Option is a choice of a select or multiselect question.
+type Option struct {
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
+}
+
+// Title returns the label shown for the option.
+func (o Option) Title() string {
+	if o.Label != "" {
+		return o.Label
+	}
+	return o.Value
+}
+
+// Question is a single input a plugin asks the user for.
+//
+// Default is a string for text and select, a []string for multiselect and a
+// bool for confirm. Values decoded from JSON ([]any) are accepted too.
+type Question struct {
+	ID       string   `json:"id"`
+	Type     Type     `json:"type"`
+	Label    string   `json:"label"`
+	Help     string   `json:"help,omitempty"`
+	Default  any      `json:"default,omitempty"`
+	Options  []Option `json:"options,omitempty"`
+	Required bool     `json:"required,omitempty"`
+
+	// Pattern is a regular expression a text answer must match.
+	Pattern string `json:"pattern,omitempty"`
+	// When hides the question unless an earlier answer matches.
+	When *Condition `json:"when,omitempty"`
+	// Check is an extra validation for built-in plugins.
+	Check func(any) error `json:"-"`
+}
+
+// Condition makes a question depend on the answer to an earlier one.
+type Condition struct {
+	ID string `json:"id"`
+	// Equals is the value the answer must have; for a multiselect answer it
+	// must be one of the selected values.
+	Equals any `json:"equals"`
+}
+
+// Matches reports whether the answers satisfy the condition.
+func (c Condition) Matches(a Answers) bool {
+	switch want := c.Equals.(type) {
+	case bool:
+		got, ok := a[c.ID].(bool)
+		return ok && got == want
+	case string:
+		if s, ok := a[c.ID].(string); ok {
+			return s == want
+		}
+		for _, s := range a.Strings(c.ID) {
+			if s == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Visible reports whether q is asked given the answers so far.
+func (q Question) Visible(a Answers) bool {
+	return q.When == nil || q.When.Matches(a)
+}
+
+// Answers maps question IDs to values: string, []string or bool.
+type Answers map[string]any
+
+func (a Answers) String(id string) string {
+	s, _ := a[id].(string)
+	return s
+}
+
+func (a Answers) Bool(id string) bool {
+	b, _ := a[id].(bool)
+	return b
+}
+
+func (a Answers) Strings(id string) []string {
+	return toStrings(a[id])
+}
+
+// DefaultString returns the default of a text or select question.
+func (q Question) DefaultString() string {
+	switch v := q.Default.(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// DefaultBool returns the default of a confirm question.
+func (q Question) DefaultBool() bool {
+	b, _ := q.Default.(bool)
+	return b
+}
+
+// DefaultStrings returns the default of a multiselect question.
+func (q Question) DefaultStrings() []string {
+	return toStrings(q.Default)
+}
+
+// Validate checks the question definition itself.
+func (q Question) Validate() error {
+	if strings.TrimSpace(q.ID) == "" {
+		return fmt.Errorf("question without id")
+	}
+	if q.Pattern != "" {
+		if _, err := regexp.Compile(q.Pattern); err != nil {
+			return fmt.Errorf("question %q: invalid pattern: %w", q.ID, err)
+		}
+	}
+	switch q.Type {
+	case Text, Confirm:
+	case Select, MultiSelect:
+		if len(q.Options) == 0 {
+			return fmt.Errorf("question %q: %s needs options", q.ID, q.Type)
+		}
+	default:
+		return fmt.Errorf("question %q: unknown type %q", q.ID, q.Type)
+	}
+	return nil
+}
+
+// Normalize converts a provided value to the question's answer type.
+// Strings are accepted for every type, so values can come from flags:
+// "a,b" for multiselect and "true"/"false" for confirm.
+func (q Question) Normalize(v any) (any, error) {
+	switch q.Type {
+	case Text, Select:
+		switch v := v.(type) {
+		case string:
+			return strings.TrimSpace(v), nil
+		case nil:
+			return "", nil
+		}
+		return fmt.Sprint(v), nil
+
+	case MultiSelect:
+		if s, ok := v.(string); ok {
+			var out []string
+			for _, part := range strings.Split(s, ",") {
+				if part = strings.TrimSpace(part); part != "" {
+					out = append(out, part)
+				}
+			}
+			return out, nil
+		}
+		switch v.(type) {
+		case []string, []any, nil:
+			return toStrings(v), nil
+		}
+
+	case Confirm:
+		switch v := v.(type) {
+		case bool:
+			return v, nil
+		case string:
+			b, err := strconv.ParseBool(strings.TrimSpace(v))
+			if err != nil {
+				return nil, fmt.Errorf("%s: want true or false, got %q", q.ID, v)
+			}
+			return b, nil
+		}
+	}
+	return nil, fmt.Errorf("%s: unexpected value %v for a %s question", q.ID, v, q.Type)
+}
+
+// DefaultValue returns the question's default as an answer.
+func (q Question) DefaultValue() any {
+	switch q.Type {
+	case MultiSelect:
+		return q.DefaultStrings()
+	case Confirm:
+		return q.DefaultBool()
+	}
+	return q.DefaultString()
+}
+
+// CheckAnswer validates an answer: required, known options, pattern and
+// the question's own Check.
+func (q Question) CheckAnswer(v any) error {
+	switch q.Type {
+	case Text:
+		s, _ := v.(string)
+		if q.Required && s == "" {
+			return errors.New("a value is required")
+		}
+		if q.Pattern != "" && s != "" {
+			if ok, _ := regexp.MatchString(q.Pattern, s); !ok {
+				return fmt.Errorf("must match %s", q.Pattern)
+			}
+		}
+
+	case Select:
+		s, _ := v.(string)
+		if !q.hasOption(s) {
+			return fmt.Errorf("unknown option %q", s)
+		}
+
+	case MultiSelect:
+		vals := toStrings(v)
+		if q.Required && len(vals) == 0 {
+			return errors.New("select at least one option")
+		}
+		for _, s := range vals {
+			if !q.hasOption(s) {
+				return fmt.Errorf("unknown option %q", s)
+			}
+		}
+	}
+
+	if q.Check != nil {
+		return q.Check(v)
+	}
+	return nil
+}
+
+func (q Question) hasOption(value string) bool {
+	for _, o := range q.Options {
+		if o.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve answers qs without asking: each visible question takes its value
+// from provided, or its default. Unknown IDs and invalid values are errors.
+func Resolve(qs []Question, provided Answers) (Answers, error) {
+	known := map[string]bool{}
+	for _, q := range qs {
+		known[q.ID] = true
+	}
+
+	var unknown []string
+	for id := range provided {
+		if !known[id] {
+			unknown = append(unknown, id)
+		}
+	}
+	sort.Strings(unknown)
+
+	var errs []error
+	for _, id := range unknown {
+		errs = append(errs, fmt.Errorf("unknown question %q", id))
+	}
+
+	answers := Answers{}
+	for _, q := range qs {
+		if !q.Visible(answers) {
+			continue
+		}
+
+		val := q.DefaultValue()
+		if raw, ok := provided[q.ID]; ok {
+			v, err := q.Normalize(raw)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			val = v
+		}
+		if err := q.CheckAnswer(val); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", q.ID, err))
+			continue
+		}
+		answers[q.ID] = val
+	}
+
+	return answers, errors.Join(errs...)
+}
+
+func toStrings(v any) []string {
+	switch v := v.(type) {
+	case []string:
+		return v
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, x := range v {
+			if s, ok := x.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
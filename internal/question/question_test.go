@@ -0,0 +1,174 @@
+package question
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		typ  Type
+		in   any
+		want any
+		err  bool
+	}{
+		{"text", Text, "  api  ", "api", false},
+		{"text from nil", Text, nil, "", false},
+		{"text from number", Text, 8080.0, "8080", false},
+		{"select", Select, "mit", "mit", false},
+		{"multiselect from flag", MultiSelect, " a, ,b ", []string{"a", "b"}, false},
+		{"multiselect from empty flag", MultiSelect, "", []string(nil), false},
+		{"multiselect", MultiSelect, []string{"a"}, []string{"a"}, false},
+		{"multiselect from json", MultiSelect, []any{"a", 1.0, "b"}, []string{"a", "b"}, false},
+		{"multiselect from bool", MultiSelect, true, nil, true},
+		{"confirm", Confirm, true, true, false},
+		{"confirm from flag", Confirm, " false ", false, false},
+		{"confirm from word", Confirm, "yes", nil, true},
+		{"confirm from number", Confirm, 1.0, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Question{ID: "q", Type: tt.typ}.Normalize(tt.in)
+			if (err != nil) != tt.err {
+				t.Fatalf("Normalize(%v) error = %v, want error %v", tt.in, err, tt.err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Normalize(%v) = %#v, want %#v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConditionMatches(t *testing.T) {
+	answers := Answers{
+		"docker":   true,
+		"license":  "MIT",
+		"services": []string{"postgres", "redis"},
+		"tools":    []any{"lint"},
+	}
+
+	tests := []struct {
+		name string
+		cond Condition
+		want bool
+	}{
+		{"bool", Condition{ID: "docker", Equals: true}, true},
+		{"bool mismatch", Condition{ID: "docker", Equals: false}, false},
+		{"string", Condition{ID: "license", Equals: "MIT"}, true},
+		{"string mismatch", Condition{ID: "license", Equals: "Apache-2.0"}, false},
+		{"selected value", Condition{ID: "services", Equals: "redis"}, true},
+		{"unselected value", Condition{ID: "services", Equals: "nats"}, false},
+		{"selected json value", Condition{ID: "tools", Equals: "lint"}, true},
+		{"missing answer", Condition{ID: "other", Equals: "x"}, false},
+		{"missing bool", Condition{ID: "other", Equals: false}, false},
+		{"type mismatch", Condition{ID: "license", Equals: true}, false},
+		{"unsupported equals", Condition{ID: "license", Equals: 1.0}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cond.Matches(answers); got != tt.want {
+				t.Errorf("Matches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	qs := []Question{
+		{ID: "name", Type: Text, Required: true, Default: "app", Pattern: `^[a-z]+$`},
+		{ID: "license", Type: Select, Default: "MIT", Options: []Option{{Value: "MIT"}, {Value: "ISC"}}},
+		{ID: "docker", Type: Confirm},
+		{ID: "services", Type: MultiSelect, Options: []Option{{Value: "postgres"}, {Value: "redis"}}, When: &Condition{ID: "docker", Equals: true}},
+	}
+
+	tests := []struct {
+		name     string
+		provided Answers
+		want     Answers
+		errs     []string
+	}{
+		{
+			name:     "defaults",
+			provided: Answers{},
+			want:     Answers{"name": "app", "license": "MIT", "docker": false},
+		},
+		{
+			name:     "provided from flags",
+			provided: Answers{"name": "api", "license": "ISC", "docker": "true", "services": "redis"},
+			want:     Answers{"name": "api", "license": "ISC", "docker": true, "services": []string{"redis"}},
+		},
+		{
+			name:     "hidden question is not answered",
+			provided: Answers{"services": "redis"},
+			want:     Answers{"name": "app", "license": "MIT", "docker": false},
+		},
+		{
+			name:     "invalid values",
+			provided: Answers{"name": "API", "license": "GPL", "docker": "maybe"},
+			want:     Answers{},
+			errs:     []string{`name: must match`, `license: unknown option "GPL"`, `docker: want true or false`},
+		},
+		{
+			name:     "required",
+			provided: Answers{"name": ""},
+			want:     Answers{"license": "MIT", "docker": false},
+			errs:     []string{"name: a value is required"},
+		},
+		{
+			name:     "unknown option in multiselect",
+			provided: Answers{"docker": true, "services": []any{"nats"}},
+			want:     Answers{"name": "app", "license": "MIT", "docker": true},
+			errs:     []string{`services: unknown option "nats"`},
+		},
+		{
+			name:     "unknown question",
+			provided: Answers{"colour": "red"},
+			want:     Answers{"name": "app", "license": "MIT", "docker": false},
+			errs:     []string{`unknown question "colour"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Resolve(qs, tt.provided)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("answers = %#v, want %#v", got, tt.want)
+			}
+			if len(tt.errs) == 0 && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			for _, want := range tt.errs {
+				if err == nil || !strings.Contains(err.Error(), want) {
+					t.Errorf("error = %v, want it to mention %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		q    Question
+		err  string
+	}{
+		{"text", Question{ID: "a", Type: Text}, ""},
+		{"no id", Question{Type: Text}, "without id"},
+		{"select without options", Question{ID: "a", Type: Select}, "needs options"},
+		{"bad pattern", Question{ID: "a", Type: Text, Pattern: "("}, "invalid pattern"},
+		{"unknown type", Question{ID: "a", Type: "slider"}, "unknown type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.q.Validate()
+			if tt.err == "" && err != nil || tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+				t.Errorf("Validate = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package runner
+
+import (
+	"github.com/ezeqielle/pcli/internal/plan"
+)
+
+// DryRun is a Runner that records commands into a plan instead of running them.
+// LookPath is delegated to base so tool detection still reflects the system.
+type DryRun struct {
+	base Runner
+	plan *plan.Plan
+}
+
+func NewDryRun(base Runner, p *plan.Plan) *DryRun {
+	return &DryRun{base: base, plan: p}
+}
+
+func (d *DryRun) Run(cmd Command) ([]byte, error) {
+	d.plan.AddCommand(cmd.String(), cmd.Dir)
+	return nil, nil
+}
+
+func (d *DryRun) Stream(cmd Command, ch chan<- string) error {
+	d.plan.AddCommand(cmd.String(), cmd.Dir)
+	ch <- "(dry run) " + cmd.String()
+	return nil
+}
+
+func (d *DryRun) LookPath(file string) (string, error) {
+	return d.base.LookPath(file)
+}
@@ -0,0 +1,156 @@
+package runner
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// ExitError is returned by Fake when a scripted command exits non-zero.
+type ExitError struct {
+	Command Command
+	Code    int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("%s: exit status %d", e.Command, e.Code)
+}
+
+// Response is the canned result of a scripted command.
+type Response struct {
+	// Match is compared against Command.String(). A trailing "*" matches
+	// any command line starting with the text before it.
+	Match string
+
+	Output   string
+	ExitCode int
+	Err      error
+}
+
+func (r Response) matches(cmd Command) bool {
+	line := cmd.String()
+	if prefix, ok := strings.CutSuffix(r.Match, "*"); ok {
+		return strings.HasPrefix(line, prefix)
+	}
+	return line == r.Match
+}
+
+// Fake is a scripted Runner for tests.
+//
+// It records every invocation in order and replays canned output and exit
+// codes. Commands without a matching response succeed with no output.
+// Responses are consumed in registration order; the last matching response
+// for a command line is reused once the earlier ones are exhausted.
+type Fake struct {
+	mu        sync.Mutex
+	calls     []Command
+	responses []Response
+	used      []bool
+	paths     map[string]string
+}
+
+// NewFake returns an empty Fake runner.
+func NewFake() *Fake {
+	return &Fake{paths: map[string]string{}}
+}
+
+// On scripts the output and exit code for a command line.
+func (f *Fake) On(match, output string, exitCode int) *Fake {
+	return f.Script(Response{Match: match, Output: output, ExitCode: exitCode})
+}
+
+// Script registers a full Response.
+func (f *Fake) Script(r Response) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses = append(f.responses, r)
+	f.used = append(f.used, false)
+	return f
+}
+
+// WithPath makes LookPath find file at path.
+func (f *Fake) WithPath(file, path string) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.paths[file] = path
+	return f
+}
+
+// Calls returns every command executed so far, in order.
+func (f *Fake) Calls() []Command {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	out := make([]Command, len(f.calls))
+	copy(out, f.calls)
+	return out
+}
+
+// CommandLines returns Calls rendered with Command.String.
+func (f *Fake) CommandLines() []string {
+	calls := f.Calls()
+	out := make([]string, len(calls))
+	for i, c := range calls {
+		out[i] = c.String()
+	}
+	return out
+}
+
+func (f *Fake) Run(cmd Command) ([]byte, error) {
+	r := f.record(cmd)
+	return []byte(r.Output), r.result(cmd)
+}
+
+func (f *Fake) Stream(cmd Command, ch chan<- string) error {
+	r := f.record(cmd)
+	if r.Output != "" {
+		for _, line := range strings.Split(strings.TrimRight(r.Output, "\n"), "\n") {
+			ch <- line
+		}
+	}
+	return r.result(cmd)
+}
+
+func (f *Fake) LookPath(file string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if p, ok := f.paths[file]; ok {
+		return p, nil
+	}
+	return "", &exec.Error{Name: file, Err: exec.ErrNotFound}
+}
+
+func (f *Fake) record(cmd Command) Response {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, cmd)
+
+	last := -1
+	for i, r := range f.responses {
+		if !r.matches(cmd) {
+			continue
+		}
+		if !f.used[i] {
+			f.used[i] = true
+			return r
+		}
+		last = i
+	}
+	if last >= 0 {
+		return f.responses[last]
+	}
+	return Response{}
+}
+
+func (r Response) result(cmd Command) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	if r.ExitCode != 0 {
+		return &ExitError{Command: cmd, Code: r.ExitCode}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package runner
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFakeResponses(t *testing.T) {
+	f := NewFake().
+		On("git status", "first", 0).
+		On("git status", "second", 1).
+		On("go mod *", "mod", 0)
+
+	tests := []struct {
+		cmd  Command
+		out  string
+		code int
+	}{
+		{Cmd("git", "status"), "first", 0},
+		{Cmd("git", "status"), "second", 1},
+		{Cmd("git", "status"), "second", 1},
+		{Cmd("go", "mod", "tidy"), "mod", 0},
+		{Cmd("go", "build"), "", 0},
+	}
+
+	for _, tt := range tests {
+		out, err := f.Run(tt.cmd)
+		if string(out) != tt.out {
+			t.Errorf("Run(%s) output = %q, want %q", tt.cmd, out, tt.out)
+		}
+		var exit *ExitError
+		switch {
+		case tt.code == 0 && err != nil:
+			t.Errorf("Run(%s) error = %v", tt.cmd, err)
+		case tt.code != 0 && (!errors.As(err, &exit) || exit.Code != tt.code):
+			t.Errorf("Run(%s) error = %v, want exit status %d", tt.cmd, err, tt.code)
+		}
+	}
+
+	want := []string{"git status", "git status", "git status", "go mod tidy", "go build"}
+	if got := f.CommandLines(); !reflect.DeepEqual(got, want) {
+		t.Errorf("CommandLines = %q, want %q", got, want)
+	}
+}
+
+func TestFakeStream(t *testing.T) {
+	f := NewFake().On("make", "a\nb\n", 0)
+
+	ch := make(chan string, 4)
+	if err := f.Stream(Cmd("make").In("/p"), ch); err != nil {
+		t.Fatal(err)
+	}
+	close(ch)
+
+	var lines []string
+	for l := range ch {
+		lines = append(lines, l)
+	}
+	if want := []string{"a", "b"}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("lines = %q, want %q", lines, want)
+	}
+	if got := f.Calls()[0].Dir; got != "/p" {
+		t.Errorf("Dir = %q, want /p", got)
+	}
+}
+
+func TestFakeLookPath(t *testing.T) {
+	f := NewFake().WithPath("go", "/usr/bin/go")
+
+	if p, err := f.LookPath("go"); err != nil || p != "/usr/bin/go" {
+		t.Errorf("LookPath(go) = %q, %v", p, err)
+	}
+	if _, err := f.LookPath("cargo"); err == nil {
+		t.Error("LookPath(cargo) found a binary that was not registered")
+	}
+}
@@ -0,0 +1,110 @@
+package runner
+
+import (
+	"bufio"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// Command describes an external program invocation.
+type Command struct {
+	Name string
+	Args []string
+	Dir  string
+}
+
+// Cmd builds a Command for name and args.
+func Cmd(name string, args ...string) Command {
+	return Command{Name: name, Args: args}
+}
+
+// In returns a copy of the command that runs inside dir.
+func (c Command) In(dir string) Command {
+	c.Dir = dir
+	return c
+}
+
+// String renders the command line as it would be typed in a shell.
+func (c Command) String() string {
+	parts := make([]string, 0, len(c.Args)+1)
+	parts = append(parts, c.Name)
+	parts = append(parts, c.Args...)
+	return strings.Join(parts, " ")
+}
+
+// Runner executes external commands.
+//
+// Every place in pcli that needs to spawn a process goes through a Runner,
+// so the real implementation can be swapped for a Fake in tests.
+type Runner interface {
+	// Run executes cmd and returns its combined stdout and stderr.
+	Run(cmd Command) ([]byte, error)
+
+	// Stream executes cmd and pushes each stdout/stderr line into ch.
+	// The caller owns ch and is responsible for closing it after Stream returns.
+	Stream(cmd Command, ch chan<- string) error
+
+	// LookPath searches for an executable named file in PATH.
+	LookPath(file string) (string, error)
+}
+
+// Exec is the Runner backed by os/exec.
+type Exec struct{}
+
+// NewExec returns a Runner that executes real processes.
+func NewExec() *Exec {
+	return &Exec{}
+}
+
+func (e *Exec) Run(cmd Command) ([]byte, error) {
+	c := exec.Command(cmd.Name, cmd.Args...)
+	c.Dir = cmd.Dir
+	return c.CombinedOutput()
+}
+
+func (e *Exec) Stream(cmd Command, ch chan<- string) error {
+	c := exec.Command(cmd.Name, cmd.Args...)
+	c.Dir = cmd.Dir
+
+	stdout, err := c.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to get stdout pipe: %w", err)
+	}
+
+	stderr, err := c.StderrPipe()
+	if err != nil {
+		return fmt.Errorf("failed to get stderr pipe: %w", err)
+	}
+
+	if err := c.Start(); err != nil {
+		return fmt.Errorf("failed to start command: %w", err)
+	}
+
+	// Read stderr in a goroutine
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			ch <- scanner.Text()
+		}
+	}()
+
+	// Read stdout in current goroutine
+	stdoutScanner := bufio.NewScanner(stdout)
+	for stdoutScanner.Scan() {
+		ch <- stdoutScanner.Text()
+	}
+
+	// Both pipes must be drained before Wait closes them
+	wg.Wait()
+
+	return c.Wait()
+}
+
+func (e *Exec) LookPath(file string) (string, error) {
+	return exec.LookPath(file)
+}
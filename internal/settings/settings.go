@@ -0,0 +1,207 @@
+package settings
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ezeqielle/pcli/internal/fsys"
+)
+
+// Config is pcli's own configuration: which plugins are offered, in what
+// order, and how their items are pre-selected.
+//
+//	{
+//	  "project_types": {"disabled": ["terraform"]},
+//	  "post_create":   {"order": ["global", "go_layout"]},
+//	  "items":         {"global_gitignore": "selected", "go_gen": "hidden"},
+//	  "ui":            {"theme": "ocean"}
+//	}
+type Config struct {
+	ProjectTypes Selection `json:"project_types"`
+	PostCreate   Selection `json:"post_create"`
+	Items        Items     `json:"items"`
+	UI           UI        `json:"ui"`
+}
+
+// UI configures how the wizards look. NO_COLOR and PCLI_THEME in the
+// environment take precedence.
+type UI struct {
+	// Theme names a color theme, e.g. default, ocean, mono or high-contrast.
+	Theme string `json:"theme,omitempty"`
+	// HighContrast selects the high-contrast theme whatever Theme says.
+	HighContrast bool `json:"high_contrast,omitempty"`
+}
+
+// Selection enables, disables and orders plugins by ID.
+type Selection struct {
+	// Enabled, when non-empty, is the only set of plugins offered.
+	Enabled []string `json:"enabled,omitempty"`
+	// Disabled plugins are never offered.
+	Disabled []string `json:"disabled,omitempty"`
+	// Order lists plugin IDs that come first, in this order; the others
+	// follow in their default order.
+	Order []string `json:"order,omitempty"`
+}
+
+// Allows reports whether the plugin id may be offered.
+func (s Selection) Allows(id string) bool {
+	if contains(s.Disabled, id) {
+		return false
+	}
+	return len(s.Enabled) == 0 || contains(s.Enabled, id)
+}
+
+// Apply returns the indexes of ids that are allowed, with the ones named in
+// Order first. The relative order of the remaining ids is kept.
+func (s Selection) Apply(ids []string) []int {
+	var out []int
+	for _, want := range s.Order {
+		for i, id := range ids {
+			if id == want && s.Allows(id) {
+				out = append(out, i)
+			}
+		}
+	}
+	for i, id := range ids {
+		if s.Allows(id) && !contains(s.Order, id) {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+func (s Selection) validate(section string) error {
+	for _, id := range s.Enabled {
+		if contains(s.Disabled, id) {
+			return fmt.Errorf("%s: %q is both enabled and disabled", section, id)
+		}
+	}
+	return nil
+}
+
+// ItemState is the default state of a wizard item.
+type ItemState string
+
+const (
+	// Selected items are ticked by default.
+	Selected ItemState = "selected"
+	// Unselected items are offered but not ticked.
+	Unselected ItemState = "unselected"
+	// Hidden items are never offered.
+	Hidden ItemState = "hidden"
+)
+
+// Items maps item IDs (e.g. global_gitignore, go_gen) to their default state.
+type Items map[string]ItemState
+
+// Default returns whether the item id is offered and whether it starts
+// selected, given the plugin's own default.
+func (it Items) Default(id string, selected bool) (bool, bool) {
+	switch it[id] {
+	case Selected:
+		return true, true
+	case Unselected:
+		return true, false
+	case Hidden:
+		return false, false
+	}
+	return true, selected
+}
+
+// Filter applies Items.Default to a plugin's list of items: hidden items
+// are dropped and the others get their configured selection. state returns
+// an item's ID and a pointer to its selected field.
+func Filter[T any](cfg Items, items []T, state func(*T) (string, *bool)) []T {
+	var out []T
+	for _, it := range items {
+		id, selected := state(&it)
+		offered, sel := cfg.Default(id, *selected)
+		if !offered {
+			continue
+		}
+		*selected = sel
+		out = append(out, it)
+	}
+	return out
+}
+
+// Validate checks the configuration for contradictions and unknown values.
+func (c *Config) Validate() error {
+	if err := c.ProjectTypes.validate("project_types"); err != nil {
+		return err
+	}
+	if err := c.PostCreate.validate("post_create"); err != nil {
+		return err
+	}
+	for id, state := range c.Items {
+		switch state {
+		case Selected, Unselected, Hidden:
+		default:
+			return fmt.Errorf("items: %q has unknown state %q (want selected, unselected or hidden)", id, state)
+		}
+	}
+	return nil
+}
+
+// DefaultPath returns $PCLI_CONFIG when set, otherwise
+// $XDG_CONFIG_HOME/pcli/config.json (default ~/.config/pcli/config.json).
+func DefaultPath() (string, error) {
+	if path := os.Getenv("PCLI_CONFIG"); path != "" {
+		return path, nil
+	}
+
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" && filepath.IsAbs(dir) {
+		return filepath.Join(dir, "pcli", "config.json"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "pcli", "config.json"), nil
+}
+
+// Load reads the configuration at path. A missing file yields the empty
+// configuration, which offers every plugin with its own defaults.
+func Load(fs fsys.FS, path string) (*Config, error) {
+	cfg := &Config{}
+
+	data, err := fs.ReadFile(path)
+	if err != nil {
+		if fsys.IsNotExist(err) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// LoadDefault reads the configuration at DefaultPath.
+func LoadDefault(fs fsys.FS) (*Config, error) {
+	path, err := DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return Load(fs, path)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package taskrunner
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"text/template"
+)
+
+// Templates use [[ ]] so the {{ }} of Taskfile and just pass through.
+//
+//go:embed templates
+var templates embed.FS
+
+// Format is a task runner pcli can write targets for.
+type Format struct {
+	ID    string
+	Label string
+	// File is the file the runner reads, relative to the project.
+	File string
+}
+
+var formats = []Format{
+	{ID: "make", Label: "Make", File: "Makefile"},
+	{ID: "task", Label: "Task", File: "Taskfile.yml"},
+	{ID: "just", Label: "just", File: "justfile"},
+}
+
+// Formats returns the supported task runners; the first is the default.
+func Formats() []Format {
+	return append([]Format(nil), formats...)
+}
+
+// Lookup returns the format with the given ID.
+func Lookup(id string) (Format, bool) {
+	for _, f := range formats {
+		if f.ID == id {
+			return f, true
+		}
+	}
+	return Format{}, false
+}
+
+// Next returns the format after f, wrapping around.
+func (f Format) Next() Format {
+	for i, g := range formats {
+		if g.ID == f.ID {
+			return formats[(i+1)%len(formats)]
+		}
+	}
+	return formats[0]
+}
+
+// Project is what the templates know about the project.
+type Project struct {
+	Name   string
+	Binary string
+	Module string
+	// Main is the main package, e.g. ./cmd/<binary> or ".".
+	Main string
+}
+
+// typeTemplates lists the project types with their own targets; others
+// get a placeholder target.
+var typeTemplates = map[string]bool{
+	"go": true,
+}
+
+// Render returns the task file of format f for a project of projectType.
+func Render(f Format, projectType string, p Project) ([]byte, error) {
+	kind := "generic"
+	if typeTemplates[projectType] {
+		kind = projectType
+	}
+
+	name := "templates/" + kind + "." + f.File
+	t, err := template.New(f.File).Delims("[[", "]]").ParseFS(templates, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", name, err)
+	}
+
+	var b bytes.Buffer
+	if err := t.ExecuteTemplate(&b, kind+"."+f.File, p); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,114 @@
+package taskrunner
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	p := Project{Name: "api", Binary: "api", Module: "example.com/api", Main: "./cmd/api"}
+
+	tests := []struct {
+		format      string
+		projectType string
+		want        []string
+		unwanted    []string
+	}{
+		{
+			format:      "make",
+			projectType: "go",
+			want: []string{
+				"# api – run `make help`",
+				"BINARY  := api\n",
+				"MODULE  := example.com/api\n",
+				"MAIN    ?= ./cmd/api\n",
+				"build: ## Build the binary into bin/\n\tgo build -trimpath -o $(BIN_DIR)/$(BINARY) $(MAIN)\n",
+				"\tgo test -race -coverprofile=coverage.out $(MODULE)/...\n",
+			},
+		},
+		{
+			format:      "task",
+			projectType: "go",
+			want: []string{
+				"version: '3'\n",
+				"  BINARY: api\n",
+				"  MAIN: './cmd/api'\n",
+				"      - go build -trimpath -o {{.BIN_DIR}}/{{.BINARY}} {{.MAIN}}\n",
+			},
+			unwanted: []string{"[["},
+		},
+		{
+			format:      "just",
+			projectType: "go",
+			want: []string{
+				"binary := \"api\"\n",
+				"module := \"example.com/api\"\n",
+				"build:\n    go build -trimpath -o {{bin_dir}}/{{binary}} {{main}}\n",
+			},
+			unwanted: []string{"[[", "\t"},
+		},
+		{
+			format:      "make",
+			projectType: "rust",
+			want:        []string{"# api\n", "all:\n\t@echo"},
+			unwanted:    []string{"go build"},
+		},
+		{
+			format:      "task",
+			projectType: "",
+			want:        []string{"# api\nversion: '3'\n", "  default:\n"},
+		},
+		{
+			format:      "just",
+			projectType: "terraform",
+			want:        []string{"# api\n", "default:\n    @echo"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format+"/"+tt.projectType, func(t *testing.T) {
+			f, ok := Lookup(tt.format)
+			if !ok {
+				t.Fatalf("unknown format %q", tt.format)
+			}
+			data, err := Render(f, tt.projectType, p)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := string(data)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("%s does not contain %q:\n%s", f.File, want, got)
+				}
+			}
+			for _, unwanted := range tt.unwanted {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("%s contains %q:\n%s", f.File, unwanted, got)
+				}
+			}
+		})
+	}
+}
+
+func TestFormats(t *testing.T) {
+	seen := map[string]bool{}
+	f := Formats()[0]
+	for range Formats() {
+		if seen[f.ID] {
+			t.Fatalf("Next cycles back to %s early", f.ID)
+		}
+		seen[f.ID] = true
+		f = f.Next()
+	}
+	if f != Formats()[0] {
+		t.Errorf("Next did not wrap around to %s", Formats()[0].ID)
+	}
+
+	if _, ok := Lookup("rake"); ok {
+		t.Error("Lookup found an unknown format")
+	}
+	if got := (Format{ID: "rake"}).Next(); got != Formats()[0] {
+		t.Errorf("Next of an unknown format = %s, want the default", got.ID)
+	}
+}
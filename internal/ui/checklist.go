@@ -0,0 +1,121 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// CheckItem is one row of a Checklist.
+type CheckItem struct {
+	ID       string
+	Label    string
+	Selected bool
+	// Note is shown after the label, e.g. "(if exists: skip)".
+	Note string
+}
+
+// Checklist is a multi-select list: ↑/↓ (or k/j) move, space toggles,
+// a selects all and n selects none. Enter is left to the owner.
+type Checklist struct {
+	Items  []CheckItem
+	cursor int
+	height int
+}
+
+func NewChecklist(items []CheckItem) Checklist {
+	return Checklist{Items: items}
+}
+
+// SetHeight limits the list to rows lines, scrolling with the cursor;
+// 0 shows every item.
+func (c *Checklist) SetHeight(rows int) {
+	c.height = rows
+}
+
+// Cursor returns the index of the highlighted item.
+func (c Checklist) Cursor() int {
+	return c.cursor
+}
+
+// IsSelected reports whether the item with the given ID is ticked.
+func (c Checklist) IsSelected(id string) bool {
+	for _, it := range c.Items {
+		if it.ID == id {
+			return it.Selected
+		}
+	}
+	return false
+}
+
+// Selected returns the IDs of the ticked items, in list order.
+func (c Checklist) Selected() []string {
+	var out []string
+	for _, it := range c.Items {
+		if it.Selected {
+			out = append(out, it.ID)
+		}
+	}
+	return out
+}
+
+func (c Checklist) Update(msg tea.Msg) (Checklist, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok || len(c.Items) == 0 {
+		return c, nil
+	}
+
+	switch key.String() {
+	case "up", "k":
+		c.cursor = (c.cursor - 1 + len(c.Items)) % len(c.Items)
+	case "down", "j":
+		c.cursor = (c.cursor + 1) % len(c.Items)
+	case " ":
+		c.Items[c.cursor].Selected = !c.Items[c.cursor].Selected
+	case "a":
+		for i := range c.Items {
+			c.Items[i].Selected = true
+		}
+	case "n":
+		for i := range c.Items {
+			c.Items[i].Selected = false
+		}
+	}
+	return c, nil
+}
+
+func (c Checklist) View() string {
+	if len(c.Items) == 0 {
+		return "  (nothing to select)\n"
+	}
+
+	start, end := window(len(c.Items), c.cursor, c.height)
+
+	var b strings.Builder
+	b.WriteString(moreLine("↑", start))
+	for i := start; i < end; i++ {
+		it := c.Items[i]
+		check := "[ ]"
+		if it.Selected {
+			check = "[x]"
+		}
+		switch {
+		case i == c.cursor:
+			b.WriteString(styles.Cursor.Render(fmt.Sprintf("> %s %s", check, it.Label)))
+		case it.Selected:
+			fmt.Fprintf(&b, "  %s %s", styles.Checked.Render(check), it.Label)
+		default:
+			fmt.Fprintf(&b, "  %s %s", check, it.Label)
+		}
+		if it.Note != "" {
+			b.WriteString("  " + styles.Note.Render(it.Note))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(moreLine("↓", len(c.Items)-end))
+	return b.String()
+}
+
+// ChecklistKeys is the key help for a Checklist.
+const ChecklistKeys = "[↑/↓] Move  [space] Toggle  [a] All  [n] None"
@@ -0,0 +1,63 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Confirm is a yes/no dialog: y and n answer directly, ←/→ (or tab) move
+// the choice and enter answers with it.
+type Confirm struct {
+	Prompt string
+	yes    bool
+}
+
+// NewConfirm returns a dialog with def pre-selected.
+func NewConfirm(prompt string, def bool) Confirm {
+	return Confirm{Prompt: prompt, yes: def}
+}
+
+// Answer reports the answer key gives, if any.
+func (c Confirm) Answer(key tea.KeyMsg) (value bool, ok bool) {
+	switch key.String() {
+	case "y", "Y":
+		return true, true
+	case "n", "N":
+		return false, true
+	case "enter":
+		return c.yes, true
+	}
+	return false, false
+}
+
+func (c Confirm) Update(msg tea.Msg) (Confirm, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return c, nil
+	}
+
+	switch key.String() {
+	case "left", "h":
+		c.yes = true
+	case "right", "l":
+		c.yes = false
+	case "tab":
+		c.yes = !c.yes
+	}
+	return c, nil
+}
+
+func (c Confirm) View() string {
+	yes, no := styles.Button.Render("  Yes  "), styles.ActiveButton.Render("[ No ]")
+	if c.yes {
+		yes, no = styles.ActiveButton.Render("[ Yes ]"), styles.Button.Render("  No  ")
+	}
+
+	view := ""
+	if c.Prompt != "" {
+		view = c.Prompt + "\n\n"
+	}
+	return view + "  " + yes + "  " + no + "\n"
+}
+
+// ConfirmKeys is the key help for a Confirm.
+const ConfirmKeys = "[y] Yes  [n] No  [←/→] Choose  [enter] Confirm"
@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// TreeStatus tells how a path in a file tree relates to the project.
+type TreeStatus int
+
+const (
+	// TreeExisting paths are already there and left alone.
+	TreeExisting TreeStatus = iota
+	// TreeNew paths will be created.
+	TreeNew
+	// TreeChanged paths exist and will be changed, e.g. overwritten or merged.
+	TreeChanged
+	// TreeKept paths exist, were selected, and are kept as they are.
+	TreeKept
+)
+
+// TreeEntry is a path in a file tree, relative to its root and separated
+// by "/".
+type TreeEntry struct {
+	Path   string
+	Dir    bool
+	Status TreeStatus
+	// Note is shown after the name, e.g. the conflict policy.
+	Note string
+}
+
+type treeNode struct {
+	entry    *TreeEntry
+	children map[string]*treeNode
+}
+
+// FileTree renders entries as a tree under root, colored by status.
+// Parents missing from entries are drawn as existing directories.
+func FileTree(root string, entries []TreeEntry) string {
+	top := &treeNode{children: map[string]*treeNode{}}
+	for i := range entries {
+		cur := top
+		for _, part := range strings.Split(path.Clean(entries[i].Path), "/") {
+			next, ok := cur.children[part]
+			if !ok {
+				next = &treeNode{children: map[string]*treeNode{}}
+				cur.children[part] = next
+			}
+			cur = next
+		}
+		cur.entry = &entries[i]
+	}
+
+	var b strings.Builder
+	b.WriteString(root + "/\n")
+	writeTreeNodes(&b, top, "")
+	return b.String()
+}
+
+func writeTreeNodes(b *strings.Builder, n *treeNode, indent string) {
+	names := make([]string, 0, len(n.children))
+	for name := range n.children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for i, name := range names {
+		child := n.children[name]
+		branch, nextIndent := "├── ", indent+"│   "
+		if i == len(names)-1 {
+			branch, nextIndent = "└── ", indent+"    "
+		}
+
+		e := child.entry
+		if e == nil {
+			e = &TreeEntry{Dir: true}
+		}
+		label := name
+		if e.Dir || len(child.children) > 0 {
+			label += "/"
+		}
+		b.WriteString(styles.Note.Render(indent+branch) + treeLabel(label, *e) + "\n")
+		writeTreeNodes(b, child, nextIndent)
+	}
+}
+
+func treeLabel(label string, e TreeEntry) string {
+	mark := "  "
+	switch e.Status {
+	case TreeNew:
+		mark, label = "+ ", styles.Checked.Render(label)
+	case TreeChanged:
+		mark, label = "~ ", styles.Warn.Render(label)
+	case TreeKept:
+		mark = "= "
+	default:
+		label = styles.Note.Render(label)
+	}
+	if e.Note != "" {
+		label += "  " + styles.Note.Render("("+e.Note+")")
+	}
+	return mark + label
+}
+
+// TreeLegend explains the marks FileTree uses.
+const TreeLegend = "+ new  ~ changed  = kept as is"
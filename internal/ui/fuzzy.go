@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"strings"
+	"unicode"
+)
+
+// fuzzyScore matches query against text as a case-insensitive subsequence
+// and scores the match: consecutive letters and letters starting a word
+// count more, skipped letters count less. ok is false when query is not a
+// subsequence of text.
+func fuzzyScore(query, text string) (score int, ok bool) {
+	q := []rune(strings.ToLower(query))
+	t := []rune(strings.ToLower(text))
+	if len(q) == 0 {
+		return 0, true
+	}
+
+	qi, last := 0, -1
+	for ti := 0; ti < len(t) && qi < len(q); ti++ {
+		if t[ti] != q[qi] {
+			continue
+		}
+
+		score++
+		switch {
+		case ti == 0:
+			score += 6
+		case last == ti-1:
+			score += 4
+		case !unicode.IsLetter(t[ti-1]) && !unicode.IsDigit(t[ti-1]):
+			score += 3
+		}
+		if last >= 0 {
+			score -= min(ti-last-1, 3)
+		}
+
+		last = ti
+		qi++
+	}
+
+	if qi < len(q) {
+		return 0, false
+	}
+	return score, true
+}
+
+// bestScore returns the best fuzzy score of query over fields; the first
+// field (the name) counts double.
+func bestScore(query string, fields ...string) (int, bool) {
+	best, found := 0, false
+	for i, f := range fields {
+		score, ok := fuzzyScore(query, f)
+		if !ok {
+			continue
+		}
+		if i == 0 {
+			score *= 2
+		}
+		if !found || score > best {
+			best, found = score, true
+		}
+	}
+	return best, found
+}
@@ -0,0 +1,20 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Header renders a wizard screen title with its position in the wizard;
+// total 0 leaves the position out.
+func Header(title string, step, total int) string {
+	if total <= 0 {
+		return styles.Title.Render(title) + "\n\n"
+	}
+	return styles.Title.Render(title) + "  " + styles.Step.Render(fmt.Sprintf("(step %d/%d)", step, total)) + "\n\n"
+}
+
+// Help renders a key help line from bindings like "[enter] Apply".
+func Help(bindings ...string) string {
+	return "\n" + styles.Help.Render(strings.Join(bindings, "  ")) + "\n"
+}
@@ -0,0 +1,27 @@
+package ui
+
+import "fmt"
+
+// window returns the range of n rows to show so that cursor stays visible
+// in at most height rows; height 0 shows every row.
+func window(n, cursor, height int) (int, int) {
+	if height <= 0 || n <= height {
+		return 0, n
+	}
+	start := cursor - height/2
+	if start < 0 {
+		start = 0
+	}
+	if start+height > n {
+		start = n - height
+	}
+	return start, start + height
+}
+
+// moreLine tells how many rows a windowed list hides on one side.
+func moreLine(arrow string, hidden int) string {
+	if hidden <= 0 {
+		return ""
+	}
+	return styles.Note.Render(fmt.Sprintf("  %s %d more", arrow, hidden)) + "\n"
+}
@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxLogLines bounds how much output a LogView keeps.
+const maxLogLines = 500
+
+// LogView shows streamed command output in a scrollable viewport that
+// follows new lines until the user scrolls up. Lines wider than the
+// viewport are cut rather than wrapped and scroll sideways.
+type LogView struct {
+	lines    []string
+	viewport viewport.Model
+}
+
+func NewLogView(width, height int) LogView {
+	vp := viewport.New(width, height)
+	vp.SetHorizontalStep(8)
+	return LogView{viewport: vp}
+}
+
+// Append adds a line of output; blank lines are dropped.
+func (l *LogView) Append(line string) {
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+
+	follow := l.viewport.AtBottom()
+	l.lines = append(l.lines, line)
+	if len(l.lines) > maxLogLines {
+		l.lines = l.lines[len(l.lines)-maxLogLines:]
+	}
+	l.viewport.SetContent(strings.Join(l.lines, "\n"))
+	if follow {
+		l.viewport.GotoBottom()
+	}
+}
+
+// Reset drops every line.
+func (l *LogView) Reset() {
+	l.lines = nil
+	l.viewport.SetContent("")
+}
+
+// Lines returns the kept output.
+func (l LogView) Lines() []string {
+	return l.lines
+}
+
+// SetSize resizes the viewport, e.g. on tea.WindowSizeMsg.
+func (l *LogView) SetSize(width, height int) {
+	follow := l.viewport.AtBottom()
+	l.viewport.Width = width
+	l.viewport.Height = height
+	if follow {
+		l.viewport.GotoBottom()
+	}
+}
+
+func (l LogView) Update(msg tea.Msg) (LogView, tea.Cmd) {
+	var cmd tea.Cmd
+	l.viewport, cmd = l.viewport.Update(msg)
+	return l, cmd
+}
+
+func (l LogView) View() string {
+	if len(l.lines) == 0 {
+		return "  (no output yet)\n"
+	}
+	return l.viewport.View() + "\n"
+}
+
+// LogViewKeys is the key help for a LogView.
+const LogViewKeys = "[↑/↓/pgup/pgdn/←/→] Scroll"
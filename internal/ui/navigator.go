@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ezeqielle/pcli/internal/nav"
+)
+
+type screen struct {
+	id    string
+	title string
+	model tea.Model
+}
+
+// Navigator is the root model: it keeps a stack of screens so esc can go
+// back across plugin boundaries, and shows where the user is as a
+// breadcrumb. Screens push the next one with nav.Push and leave with
+// nav.Back.
+//
+// Screens left with nav.Back are kept, like a browser's forward history:
+// pushing a screen with the same ID again resumes it as it was left.
+//
+// The navigator owns the terminal size: screens get a tea.WindowSizeMsg for
+// the area below the breadcrumb whenever the terminal is resized and when
+// they come on top.
+type Navigator struct {
+	stack   []screen
+	forward []screen
+
+	width, height int
+}
+
+// crumbHeight is the number of lines the breadcrumb takes.
+const crumbHeight = 2
+
+func NewNavigator(title string, root tea.Model) Navigator {
+	return Navigator{stack: []screen{{title: title, model: root}}}
+}
+
+func (n Navigator) Init() tea.Cmd {
+	return n.top().model.Init()
+}
+
+func (n Navigator) top() *screen {
+	return &n.stack[len(n.stack)-1]
+}
+
+func (n Navigator) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case nav.PushMsg:
+		n.stack = append(n.stack[:len(n.stack):len(n.stack)], screen{id: msg.ID, title: msg.Title, model: msg.Model})
+		if last := len(n.forward) - 1; last >= 0 && msg.ID != "" && n.forward[last].id == msg.ID {
+			n.top().model = n.forward[last].model
+			n.forward = n.forward[:last:last]
+			return n, n.resize()
+		}
+		n.forward = nil
+		return n, tea.Batch(msg.Model.Init(), n.resize())
+
+	case nav.BackMsg:
+		top := n.top()
+		if b, ok := top.model.(nav.Backer); ok {
+			if model, moved := b.Back(); moved {
+				n.stack = append([]screen(nil), n.stack...)
+				n.top().model = model
+				return n, n.resize()
+			}
+		}
+		if len(n.stack) > 1 {
+			last := len(n.stack) - 1
+			n.forward = append(n.forward[:len(n.forward):len(n.forward)], n.stack[last])
+			n.stack = n.stack[:last:last]
+		}
+		return n, n.resize()
+
+	case tea.WindowSizeMsg:
+		n.width, n.height = msg.Width, msg.Height
+		msg.Height = max(msg.Height-crumbHeight, 0)
+		n.stack = append([]screen(nil), n.stack...)
+		var cmd tea.Cmd
+		n.top().model, cmd = n.top().model.Update(msg)
+		return n, cmd
+	}
+
+	// copy the stack so earlier Navigator values keep their screens
+	n.stack = append([]screen(nil), n.stack...)
+
+	top := n.top()
+	var cmd tea.Cmd
+	top.model, cmd = top.model.Update(msg)
+	return n, cmd
+}
+
+// resize tells the top screen the size it has, once the terminal size is
+// known.
+func (n Navigator) resize() tea.Cmd {
+	if n.width == 0 {
+		return nil
+	}
+	size := tea.WindowSizeMsg{Width: n.width, Height: n.height}
+	return func() tea.Msg { return size }
+}
+
+// Breadcrumb returns the titles of the stacked screens, followed by the
+// parts the top screen adds.
+func (n Navigator) Breadcrumb() string {
+	parts := make([]string, 0, len(n.stack))
+	for _, s := range n.stack {
+		parts = append(parts, s.title)
+	}
+	if c, ok := n.top().model.(nav.Crumbs); ok {
+		parts = append(parts, c.Crumbs()...)
+	}
+	return strings.Join(parts, " › ")
+}
+
+func (n Navigator) View() string {
+	view := styles.Crumb.Render(n.Breadcrumb()) + "\n\n" + n.top().model.View()
+	if n.width > 0 {
+		// wrap long lines instead of letting the terminal break them
+		view = lipgloss.NewStyle().Width(n.width).Render(view)
+	}
+	return view
+}
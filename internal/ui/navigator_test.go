@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ezeqielle/pcli/internal/nav"
+)
+
+// typed is a screen that remembers the keys typed into it.
+type typed string
+
+func (m typed) Init() tea.Cmd { return nil }
+func (m typed) View() string  { return string(m) }
+
+func (m typed) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		return m + typed(key.String()), nil
+	}
+	return m, nil
+}
+
+func TestNavigatorResume(t *testing.T) {
+	tests := []struct {
+		name   string
+		left   string
+		pushed string
+		resume bool
+	}{
+		{"same id", "files:a", "files:a", true},
+		{"same title, other id", "files:a", "files:b", false},
+		{"no id", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m tea.Model = NewNavigator("Root", typed(""))
+			m, _ = m.Update(nav.Push(tt.left, "Files", typed(""))())
+			m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+			m, _ = m.Update(nav.Back())
+
+			m, _ = m.Update(nav.Push(tt.pushed, "Files", typed(""))())
+			want := ""
+			if tt.resume {
+				want = "x"
+			}
+			if got := m.(Navigator).top().model.View(); got != want {
+				t.Errorf("pushed screen shows %q, want %q", got, want)
+			}
+		})
+	}
+}
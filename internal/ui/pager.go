@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Pager shows a document, e.g. a rendered file, in a scrollable viewport.
+// Leaving it is left to the owner.
+type Pager struct {
+	viewport viewport.Model
+}
+
+func NewPager(content string, width, height int) Pager {
+	vp := viewport.New(width, height)
+	vp.SetHorizontalStep(8)
+	vp.SetContent(content)
+	return Pager{viewport: vp}
+}
+
+// SetSize resizes the viewport, e.g. on tea.WindowSizeMsg.
+func (p *Pager) SetSize(width, height int) {
+	p.viewport.Width = width
+	p.viewport.Height = height
+}
+
+func (p Pager) Update(msg tea.Msg) (Pager, tea.Cmd) {
+	var cmd tea.Cmd
+	p.viewport, cmd = p.viewport.Update(msg)
+	return p, cmd
+}
+
+func (p Pager) View() string {
+	return p.viewport.View() + "\n"
+}
+
+// PagerKeys is the key help for a Pager.
+const PagerKeys = "[↑/↓/pgup/pgdn/←/→] Scroll"
@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Select is a single-choice list: ↑/↓ (or k/j) move the choice.
+// Enter is left to the owner.
+type Select struct {
+	Options []string
+	cursor  int
+	height  int
+}
+
+// NewSelect returns a Select over options with selected highlighted.
+func NewSelect(options []string, selected int) Select {
+	if selected < 0 || selected >= len(options) {
+		selected = 0
+	}
+	return Select{Options: options, cursor: selected}
+}
+
+// SetHeight limits the list to rows lines, scrolling with the choice;
+// 0 shows every option.
+func (s *Select) SetHeight(rows int) {
+	s.height = rows
+}
+
+// Index returns the highlighted option.
+func (s Select) Index() int {
+	return s.cursor
+}
+
+func (s Select) Update(msg tea.Msg) (Select, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok || len(s.Options) == 0 {
+		return s, nil
+	}
+
+	switch key.String() {
+	case "up", "k":
+		s.cursor = (s.cursor - 1 + len(s.Options)) % len(s.Options)
+	case "down", "j":
+		s.cursor = (s.cursor + 1) % len(s.Options)
+	}
+	return s, nil
+}
+
+func (s Select) View() string {
+	if len(s.Options) == 0 {
+		return "  (nothing to choose)\n"
+	}
+
+	start, end := window(len(s.Options), s.cursor, s.height)
+
+	var b strings.Builder
+	b.WriteString(moreLine("↑", start))
+	for i := start; i < end; i++ {
+		if i == s.cursor {
+			b.WriteString(styles.Cursor.Render(fmt.Sprintf("> (•) %s", s.Options[i])) + "\n")
+			continue
+		}
+		fmt.Fprintf(&b, "  ( ) %s\n", s.Options[i])
+	}
+	b.WriteString(moreLine("↓", len(s.Options)-end))
+	return b.String()
+}
+
+// SelectKeys is the key help for a Select.
+const SelectKeys = "[↑/↓] Move"
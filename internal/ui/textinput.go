@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TextInput is a single-line input whose value is checked by an optional
+// validator when the owner submits it.
+type TextInput struct {
+	input    textinput.Model
+	validate func(string) error
+	errMsg   string
+}
+
+// NewTextInput returns a focused input pre-filled with value.
+func NewTextInput(placeholder, value string, validate func(string) error) TextInput {
+	ti := textinput.New()
+	ti.Placeholder = placeholder
+	ti.SetValue(value)
+	ti.Focus()
+
+	return TextInput{input: ti, validate: validate}
+}
+
+// Required is a validator rejecting blank values.
+func Required(s string) error {
+	if s == "" {
+		return errors.New("a value is required")
+	}
+	return nil
+}
+
+// Focus returns the command that starts the cursor blinking.
+func (t *TextInput) Focus() tea.Cmd {
+	return t.input.Focus()
+}
+
+// Value returns the trimmed input.
+func (t TextInput) Value() string {
+	return strings.TrimSpace(t.input.Value())
+}
+
+// Submit validates the value. On failure the error is shown under the
+// input and ok is false.
+func (t *TextInput) Submit() (string, bool) {
+	val := t.Value()
+	t.errMsg = ""
+	if t.validate != nil {
+		if err := t.validate(val); err != nil {
+			t.errMsg = err.Error()
+			return val, false
+		}
+	}
+	return val, true
+}
+
+// SetError shows msg under the input, e.g. a validation error reported later.
+func (t *TextInput) SetError(msg string) {
+	t.errMsg = msg
+}
+
+func (t TextInput) Update(msg tea.Msg) (TextInput, tea.Cmd) {
+	var cmd tea.Cmd
+	t.input, cmd = t.input.Update(msg)
+	return t, cmd
+}
+
+func (t TextInput) View() string {
+	view := t.input.View() + "\n"
+	if t.errMsg != "" {
+		view += ErrorLine(t.errMsg)
+	}
+	return view
+}
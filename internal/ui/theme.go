@@ -0,0 +1,163 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ezeqielle/pcli/internal/settings"
+)
+
+// Theme holds the styles every screen renders with. Markers like ">" and
+// "[x]" are always drawn as text, so a theme only adds emphasis.
+type Theme struct {
+	Name string
+
+	Title   lipgloss.Style
+	Step    lipgloss.Style
+	Crumb   lipgloss.Style
+	Help    lipgloss.Style
+	Cursor  lipgloss.Style
+	Checked lipgloss.Style
+	Note    lipgloss.Style
+	Warn    lipgloss.Style
+	Error   lipgloss.Style
+	Info    lipgloss.Style
+
+	Button       lipgloss.Style
+	ActiveButton lipgloss.Style
+}
+
+// HighContrast is the name of the accessible theme.
+const HighContrast = "high-contrast"
+
+var themes = map[string]func() Theme{
+	"default": func() Theme {
+		return colorTheme("default", "63", "212", "241", "42", "214", "203", "75")
+	},
+	"ocean": func() Theme {
+		return colorTheme("ocean", "39", "45", "244", "86", "221", "209", "117")
+	},
+	"mono": monoTheme,
+	HighContrast: func() Theme {
+		s := lipgloss.NewStyle
+		return Theme{
+			Name:         HighContrast,
+			Title:        s().Bold(true).Underline(true).Foreground(lipgloss.Color("15")),
+			Step:         s().Bold(true).Foreground(lipgloss.Color("11")),
+			Crumb:        s().Bold(true).Foreground(lipgloss.Color("15")),
+			Help:         s().Foreground(lipgloss.Color("15")),
+			Cursor:       s().Bold(true).Reverse(true),
+			Checked:      s().Bold(true).Foreground(lipgloss.Color("11")),
+			Note:         s().Foreground(lipgloss.Color("15")),
+			Warn:         s().Bold(true).Underline(true).Foreground(lipgloss.Color("11")),
+			Error:        s().Bold(true).Foreground(lipgloss.Color("9")),
+			Info:         s().Bold(true).Foreground(lipgloss.Color("14")),
+			Button:       s().Foreground(lipgloss.Color("15")),
+			ActiveButton: s().Bold(true).Reverse(true),
+		}
+	},
+}
+
+func colorTheme(name, title, accent, muted, ok, warn, bad, info string) Theme {
+	s := lipgloss.NewStyle
+	return Theme{
+		Name:         name,
+		Title:        s().Bold(true).Foreground(lipgloss.Color(title)),
+		Step:         s().Foreground(lipgloss.Color(muted)),
+		Crumb:        s().Foreground(lipgloss.Color(muted)),
+		Help:         s().Foreground(lipgloss.Color(muted)),
+		Cursor:       s().Bold(true).Foreground(lipgloss.Color(accent)),
+		Checked:      s().Foreground(lipgloss.Color(ok)),
+		Note:         s().Foreground(lipgloss.Color(muted)),
+		Warn:         s().Foreground(lipgloss.Color(warn)),
+		Error:        s().Bold(true).Foreground(lipgloss.Color(bad)),
+		Info:         s().Foreground(lipgloss.Color(info)),
+		Button:       s().Foreground(lipgloss.Color(muted)),
+		ActiveButton: s().Bold(true).Foreground(lipgloss.Color(accent)),
+	}
+}
+
+// monoTheme uses no color at all, for NO_COLOR and plain terminals.
+func monoTheme() Theme {
+	s := lipgloss.NewStyle
+	return Theme{
+		Name:         "mono",
+		Title:        s().Bold(true),
+		Cursor:       s().Bold(true),
+		Warn:         s().Underline(true),
+		Error:        s().Bold(true),
+		ActiveButton: s().Bold(true),
+	}
+}
+
+// styles is the theme in use; screens read it when rendering.
+var styles = themes["default"]()
+
+// Styles returns the theme in use.
+func Styles() Theme {
+	return styles
+}
+
+// Themes returns the names of the built-in themes.
+func Themes() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetTheme switches to the named theme.
+func SetTheme(name string) error {
+	theme, ok := themes[name]
+	if !ok {
+		return fmt.Errorf("unknown theme %q (want one of %s)", name, strings.Join(Themes(), ", "))
+	}
+	styles = theme()
+	return nil
+}
+
+// ApplyTheme picks the theme from the configuration and the environment:
+// NO_COLOR wins, then PCLI_THEME, then the high_contrast and theme
+// settings. An unknown theme name falls back to the next choice and is
+// reported in the returned error, which is only a warning.
+func ApplyTheme(cfg settings.UI) error {
+	if os.Getenv("NO_COLOR") != "" {
+		return SetTheme("mono")
+	}
+
+	var err error
+	if name := os.Getenv("PCLI_THEME"); name != "" {
+		if err = SetTheme(name); err == nil {
+			return nil
+		}
+		err = fmt.Errorf("PCLI_THEME: %w", err)
+	}
+
+	switch {
+	case cfg.HighContrast:
+		return errors.Join(err, SetTheme(HighContrast))
+	case cfg.Theme != "":
+		if themeErr := SetTheme(cfg.Theme); themeErr != nil {
+			return errors.Join(err, themeErr, SetTheme("default"))
+		}
+		return err
+	}
+	return errors.Join(err, SetTheme("default"))
+}
+
+// ErrorLine renders an error message on its own line.
+func ErrorLine(msg string) string {
+	return "\n" + styles.Error.Render("Error: "+msg) + "\n"
+}
+
+// InfoLine renders an informational message on its own line.
+func InfoLine(msg string) string {
+	return styles.Info.Render("Info: "+msg) + "\n"
+}
@@ -2,72 +2,266 @@ package ui
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 
+	"github.com/ezeqielle/pcli/internal/nav"
+	"github.com/ezeqielle/pcli/internal/pluginmeta"
+	"github.com/ezeqielle/pcli/internal/postplugin"
 	"github.com/ezeqielle/pcli/internal/projecttype"
+	"github.com/ezeqielle/pcli/internal/runner"
 )
 
+// TypeChooserModel lists the enabled project types. Typing filters them
+// with a fuzzy search over name, ID, description and tags; without a query
+// they are grouped by category, with recently used types pinned on top.
 type TypeChooserModel struct {
-	plugins       []projecttype.Plugin
-	selectedIndex int
+	plugins []projecttype.Plugin
+	recent  []string
+	// installed tells whether each required tool is on PATH
+	installed map[string]bool
+
+	query  string
+	rows   []chooserRow
+	cursor int
+
+	width, height int
+}
+
+// chooserRow is a group header or a project type.
+type chooserRow struct {
+	header string
+	plugin int
+}
+
+// recentTypes is the number of recently used types pinned on top.
+const recentTypes = 3
+
+// groupTitles names the well-known categories.
+var groupTitles = map[string]string{
+	"language":  "Languages",
+	"framework": "Frameworks",
+	"infra":     "Infrastructure",
+	"":          "Other",
 }
 
-func NewTypeChooserModel() TypeChooserModel {
-	plugins := projecttype.All()
+// NewTypeChooserModel lists the enabled project types; recent are type IDs
+// to pin, newest first. Required tools are looked up with r.
+func NewTypeChooserModel(r runner.Runner, recent []string) TypeChooserModel {
+	plugins := projecttype.Enabled()
+
+	installed := map[string]bool{}
+	for _, p := range plugins {
+		for _, tool := range p.Metadata().RequiredTools {
+			if _, done := installed[tool]; !done {
+				_, err := r.LookPath(tool)
+				installed[tool] = err == nil
+			}
+		}
+	}
+
+	if len(recent) > recentTypes {
+		recent = recent[:recentTypes]
+	}
 
-	return TypeChooserModel{
-		plugins:       plugins,
-		selectedIndex: 0,
+	m := TypeChooserModel{
+		plugins:   plugins,
+		recent:    recent,
+		installed: installed,
 	}
+	m.filter()
+	return m
 }
 
 func (m TypeChooserModel) Init() tea.Cmd {
 	return nil
 }
 
+// filter rebuilds the rows for the query, keeping the highlighted type
+// when it still matches.
+func (m *TypeChooserModel) filter() {
+	current, hadCurrent := m.selected()
+
+	if m.query == "" {
+		m.rows = m.grouped()
+	} else {
+		m.rows = m.matches()
+	}
+
+	m.cursor = -1
+	for i, r := range m.rows {
+		if r.header != "" {
+			continue
+		}
+		if m.cursor < 0 || (hadCurrent && m.plugins[r.plugin].ID() == current.ID()) {
+			m.cursor = i
+		}
+	}
+}
+
+// grouped returns the recent types, then every other type by category.
+func (m TypeChooserModel) grouped() []chooserRow {
+	var rows []chooserRow
+
+	pinned := map[int]bool{}
+	for _, id := range m.recent {
+		for i, p := range m.plugins {
+			if p.ID() != id {
+				continue
+			}
+			if len(pinned) == 0 {
+				rows = append(rows, chooserRow{header: "Recent"})
+			}
+			pinned[i] = true
+			rows = append(rows, chooserRow{plugin: i})
+		}
+	}
+
+	byCategory := map[string][]int{}
+	for i, p := range m.plugins {
+		if !pinned[i] {
+			c := p.Metadata().Category
+			byCategory[c] = append(byCategory[c], i)
+		}
+	}
+
+	for _, c := range categoryOrder(byCategory) {
+		title, ok := groupTitles[c]
+		if !ok {
+			title = strings.ToUpper(c[:1]) + c[1:]
+		}
+		rows = append(rows, chooserRow{header: title})
+		for _, i := range byCategory[c] {
+			rows = append(rows, chooserRow{plugin: i})
+		}
+	}
+
+	return rows
+}
+
+// categoryOrder returns the well-known categories first, then the others
+// alphabetically, then uncategorized plugins.
+func categoryOrder(byCategory map[string][]int) []string {
+	var order, others []string
+	for _, c := range pluginmeta.Categories {
+		if _, ok := byCategory[c]; ok {
+			order = append(order, c)
+		}
+	}
+	for c := range byCategory {
+		if c != "" && !contains(pluginmeta.Categories, c) {
+			others = append(others, c)
+		}
+	}
+	sort.Strings(others)
+	order = append(order, others...)
+	if _, ok := byCategory[""]; ok {
+		order = append(order, "")
+	}
+	return order
+}
+
+// matches returns the types matching the query, best match first.
+func (m TypeChooserModel) matches() []chooserRow {
+	type scored struct {
+		plugin, score int
+	}
+
+	var found []scored
+	for i, p := range m.plugins {
+		meta := p.Metadata()
+		score, ok := bestScore(m.query, p.DisplayName(), p.ID(), p.Description(), strings.Join(meta.Tags, " "), meta.Category)
+		if ok {
+			found = append(found, scored{plugin: i, score: score})
+		}
+	}
+	sort.SliceStable(found, func(a, b int) bool {
+		return found[a].score > found[b].score
+	})
+
+	rows := make([]chooserRow, len(found))
+	for i, f := range found {
+		rows[i] = chooserRow{plugin: f.plugin}
+	}
+	return rows
+}
+
+func (m TypeChooserModel) selected() (projecttype.Plugin, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return nil, false
+	}
+	return m.plugins[m.rows[m.cursor].plugin], true
+}
+
+// move steps the cursor to the next project type row in direction dir,
+// wrapping around and skipping headers.
+func (m *TypeChooserModel) move(dir int) {
+	if m.cursor < 0 {
+		return
+	}
+	for i := m.cursor + dir; ; i += dir {
+		i = (i + len(m.rows)) % len(m.rows)
+		if m.rows[i].header == "" {
+			m.cursor = i
+			return
+		}
+	}
+}
+
 func (m TypeChooserModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		s := msg.String()
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		return m, nil
 
-		switch s {
+	case tea.KeyMsg:
+		switch msg.String() {
 		case "ctrl+c":
 			return m, tea.Quit
 
-		case "up", "k":
-			if len(m.plugins) == 0 {
-				return m, nil
-			}
-			m.selectedIndex--
-			if m.selectedIndex < 0 {
-				m.selectedIndex = len(m.plugins) - 1
+		case "enter":
+			if p, ok := m.selected(); ok {
+				return m, nav.Push("type:"+p.ID(), p.DisplayName(), p.NewWizard())
 			}
 			return m, nil
 
-		case "down", "j":
-			if len(m.plugins) == 0 {
-				return m, nil
-			}
-			m.selectedIndex++
-			if m.selectedIndex >= len(m.plugins) {
-				m.selectedIndex = 0
+		case "up", "ctrl+p":
+			m.move(-1)
+		case "down", "ctrl+n":
+			m.move(1)
+
+		case "esc":
+			m.query = ""
+			m.filter()
+
+		case "backspace":
+			if r := []rune(m.query); len(r) > 0 {
+				m.query = string(r[:len(r)-1])
+				m.filter()
 			}
-			return m, nil
 
-		case "enter":
-			if len(m.plugins) == 0 {
-				return m, nil
+		default:
+			if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+				m.query += string(msg.Runes)
+				m.filter()
 			}
-			plugin := m.plugins[m.selectedIndex]
-			return plugin.NewWizard(), nil
 		}
 	}
 
 	return m, nil
 }
 
+// chooserChrome is the number of lines the chooser shows around the list:
+// header, search line and key help.
+const chooserChrome = 7
+
+// sideBySide is the terminal width from which the detail pane is shown
+// next to the list rather than below it.
+const sideBySide = 80
+
 func (m TypeChooserModel) View() string {
 	if len(m.plugins) == 0 {
 		return "No project types registered.\n\n[ctrl+c] Quit\n"
@@ -75,25 +269,111 @@ func (m TypeChooserModel) View() string {
 
 	var b strings.Builder
 
-	b.WriteString("pcli – Create project\n\n")
-	b.WriteString("Select project type (↑/↓, enter):\n\n")
+	b.WriteString(Header("Create project", 0, 0))
+	if m.query == "" {
+		b.WriteString("Search: " + styles.Note.Render("type to filter") + "\n\n")
+	} else {
+		b.WriteString("Search: " + m.query + "▏\n\n")
+	}
 
-	for i, p := range m.plugins {
-		cursor := " "
-		if i == m.selectedIndex {
-			cursor = ">"
+	detail := ""
+	if p, ok := m.selected(); ok {
+		detail = m.describePlugin(p)
+	}
+
+	rows := 0
+	if m.height > 0 {
+		rows = m.height - chooserChrome
+		if m.width < sideBySide {
+			rows -= lipgloss.Height(detail) + 2
+		}
+		rows = max(rows, 3)
+	}
+	list := m.listView(rows)
+
+	if m.width >= sideBySide {
+		listCol := lipgloss.NewStyle().Width(lipgloss.Width(list) + 4).Render(list)
+		b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, listCol, detail) + "\n")
+	} else {
+		b.WriteString(list + "\n\n" + detail)
+	}
+
+	b.WriteString(Help("[type] Filter", "[↑/↓] Move", "[enter] Choose", "[esc] Clear", "[ctrl+c] Quit"))
+
+	return b.String()
+}
+
+// listView renders the rows, scrolled to fit height lines (0 for all).
+func (m TypeChooserModel) listView(height int) string {
+	if len(m.rows) == 0 {
+		return fmt.Sprintf("  No project type matches %q\n", m.query)
+	}
+
+	start, end := window(len(m.rows), m.cursor, height)
+
+	var b strings.Builder
+	b.WriteString(moreLine("↑", start))
+	for i := start; i < end; i++ {
+		r := m.rows[i]
+		switch {
+		case r.header != "":
+			b.WriteString(styles.Step.Render(r.header) + "\n")
+		case i == m.cursor:
+			b.WriteString(styles.Cursor.Render("> "+m.plugins[r.plugin].DisplayName()) + "\n")
+		default:
+			b.WriteString("  " + m.plugins[r.plugin].DisplayName() + "\n")
 		}
+	}
+	b.WriteString(moreLine("↓", len(m.rows)-end))
+	return strings.TrimSuffix(b.String(), "\n")
+}
 
-		fmt.Fprintf(
-			&b,
-			"%s %s – %s\n",
-			cursor,
-			p.DisplayName(),
-			p.Description(),
-		)
+// describePlugin renders the details of the highlighted project type: its
+// metadata, whether its required tools are installed and the post-create
+// plugins that will follow it.
+func (m TypeChooserModel) describePlugin(p projecttype.Plugin) string {
+	var b strings.Builder
+
+	meta := p.Metadata()
+	b.WriteString(styles.Title.Render(p.DisplayName()) + "\n")
+	b.WriteString(p.Description() + "\n\n")
+	if meta.Version != "" {
+		b.WriteString("Version:  " + meta.Version + "\n")
+	}
+	if meta.Author != "" {
+		b.WriteString("Author:   " + meta.Author + "\n")
+	}
+	if len(meta.Tags) > 0 {
+		b.WriteString("Tags:     " + strings.Join(meta.Tags, ", ") + "\n")
+	}
+	for i, tool := range meta.RequiredTools {
+		label := "Requires: "
+		if i > 0 {
+			label = "          "
+		}
+		status := styles.Checked.Render("✓ installed")
+		if !m.installed[tool] {
+			status = styles.Error.Render("✗ not found")
+		}
+		b.WriteString(label + tool + "  " + status + "\n")
 	}
 
-	b.WriteString("\n[ctrl+c] Quit\n")
+	var post []string
+	for _, pp := range postplugin.For(p.ID()) {
+		post = append(post, pp.DisplayName())
+	}
+	if len(post) > 0 {
+		b.WriteString("Then:     " + strings.Join(post, " → ") + "\n")
+	}
 
 	return b.String()
 }
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
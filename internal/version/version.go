@@ -0,0 +1,5 @@
+package version
+
+// Version is the pcli version, set at build time with
+// -ldflags "-X github.com/ezeqielle/pcli/internal/version.Version=...".
+var Version = "dev"
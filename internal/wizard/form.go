@@ -0,0 +1,371 @@
+package wizard
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ezeqielle/pcli/internal/question"
+	"github.com/ezeqielle/pcli/internal/ui"
+)
+
+// State tells the owner of a Form what happened on the last update.
+type State int
+
+const (
+	// Asking means a question is on screen.
+	Asking State = iota
+	// Submitted means every visible question has a valid answer.
+	Submitted
+	// Cancelled means esc was pressed on the first question; owners usually
+	// go back with nav.Back and Resume the form.
+	Cancelled
+)
+
+// Form asks a list of declarative questions one at a time with the shared
+// ui components. Questions whose When condition does not match the
+// answers so far are skipped. The owner handles ctrl+c and checks State
+// after every Update.
+type Form struct {
+	title     string
+	questions []question.Question
+	answers   question.Answers
+	errs      map[string]string
+
+	index int
+	state State
+
+	// height is the terminal height; lists scroll to fit it
+	height int
+
+	input   ui.TextInput
+	choice  ui.Select
+	list    ui.Checklist
+	confirm ui.Confirm
+}
+
+// NewForm returns a form over qs; answers pre-fill questions, e.g. when a
+// previous run is resumed.
+func NewForm(title string, qs []question.Question, answers question.Answers) Form {
+	f := Form{
+		title:     title,
+		questions: qs,
+		answers:   question.Answers{},
+		errs:      map[string]string{},
+	}
+	for id, v := range answers {
+		f.answers[id] = v
+	}
+
+	f.index = f.next(-1)
+	if f.index < 0 {
+		f.state = Submitted
+		return f
+	}
+	f.prepare()
+	return f
+}
+
+// Init starts the cursor of a text question blinking.
+func (f Form) Init() tea.Cmd {
+	if f.state != Asking || f.current().Type != question.Text {
+		return nil
+	}
+	return f.input.Focus()
+}
+
+func (f Form) State() State {
+	return f.state
+}
+
+// Answers returns the answers to the visible questions.
+func (f Form) Answers() question.Answers {
+	out := question.Answers{}
+	for _, q := range f.questions {
+		if !q.Visible(out) {
+			continue
+		}
+		if v, ok := f.answers[q.ID]; ok {
+			out[q.ID] = v
+		}
+	}
+	return out
+}
+
+// Reopen shows the last visible question again, e.g. when the owner's
+// next screen goes back.
+func (f Form) Reopen() (Form, tea.Cmd) {
+	f.state = Asking
+	last := f.prev(len(f.questions))
+	if last < 0 {
+		f.state = Submitted
+		return f, nil
+	}
+	f.index = last
+	return f, f.prepare()
+}
+
+// Resume shows the current question again after the form was cancelled,
+// e.g. when the user comes back to it from a previous screen.
+func (f Form) Resume() Form {
+	if f.state == Cancelled {
+		f.state = Asking
+	}
+	return f
+}
+
+// WithErrors reopens the form on the first question with an error, e.g.
+// after a plugin rejected the answers.
+func (f Form) WithErrors(errs map[string]string) (Form, tea.Cmd) {
+	f.errs = map[string]string{}
+	for id, msg := range errs {
+		f.errs[id] = msg
+	}
+	for i, q := range f.questions {
+		if _, ok := errs[q.ID]; ok && q.Visible(f.answers) {
+			f.index = i
+			f.state = Asking
+			return f, f.prepare()
+		}
+	}
+	return f, nil
+}
+
+func (f Form) current() question.Question {
+	return f.questions[f.index]
+}
+
+// next returns the first visible question after i, or -1.
+func (f Form) next(i int) int {
+	for j := i + 1; j < len(f.questions); j++ {
+		if f.questions[j].Visible(f.answers) {
+			return j
+		}
+	}
+	return -1
+}
+
+// prev returns the last visible question before i, or -1.
+func (f Form) prev(i int) int {
+	for j := i - 1; j >= 0; j-- {
+		if f.questions[j].Visible(f.answers) {
+			return j
+		}
+	}
+	return -1
+}
+
+// position returns the 1-based position of the current question and the
+// number of visible questions.
+func (f Form) position() (int, int) {
+	pos, total := 0, 0
+	for i, q := range f.questions {
+		if !q.Visible(f.answers) {
+			continue
+		}
+		total++
+		if i <= f.index {
+			pos++
+		}
+	}
+	return pos, total
+}
+
+// prepare loads the current question's answer (or default) into its widget.
+func (f *Form) prepare() tea.Cmd {
+	q := f.current()
+
+	val, ok := f.answers[q.ID]
+	if !ok {
+		val = q.DefaultValue()
+	}
+
+	switch q.Type {
+	case question.Text:
+		s, _ := val.(string)
+		f.input = ui.NewTextInput(q.DefaultString(), s, nil)
+		f.input.SetError(f.errs[q.ID])
+		return f.input.Focus()
+
+	case question.Select:
+		s, _ := val.(string)
+		labels := make([]string, len(q.Options))
+		selected := 0
+		for i, o := range q.Options {
+			labels[i] = o.Title()
+			if o.Value == s {
+				selected = i
+			}
+		}
+		f.choice = ui.NewSelect(labels, selected)
+
+	case question.MultiSelect:
+		current := question.Answers{q.ID: val}
+		checked := map[string]bool{}
+		for _, v := range current.Strings(q.ID) {
+			checked[v] = true
+		}
+		items := make([]ui.CheckItem, len(q.Options))
+		for i, o := range q.Options {
+			items[i] = ui.CheckItem{ID: o.Value, Label: o.Title(), Selected: checked[o.Value]}
+		}
+		f.list = ui.NewChecklist(items)
+
+	case question.Confirm:
+		b, _ := val.(bool)
+		f.confirm = ui.NewConfirm("", b)
+	}
+	f.fit()
+	return nil
+}
+
+// formChrome is the number of lines a form shows around a list: title,
+// header, help text, error and key help.
+const formChrome = 14
+
+// fit limits the lists to the terminal height.
+func (f *Form) fit() {
+	if f.height == 0 {
+		return
+	}
+	rows := max(f.height-formChrome, 3)
+	f.choice.SetHeight(rows)
+	f.list.SetHeight(rows)
+}
+
+func (f Form) Update(msg tea.Msg) (Form, tea.Cmd) {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		f.height = size.Height
+		f.fit()
+	}
+
+	if f.state != Asking {
+		return f, nil
+	}
+
+	q := f.current()
+
+	key, isKey := msg.(tea.KeyMsg)
+	if !isKey {
+		if q.Type == question.Text {
+			var cmd tea.Cmd
+			f.input, cmd = f.input.Update(msg)
+			return f, cmd
+		}
+		return f, nil
+	}
+
+	if key.String() == "esc" {
+		prev := f.prev(f.index)
+		if prev < 0 {
+			f.state = Cancelled
+			return f, nil
+		}
+		f.index = prev
+		return f, f.prepare()
+	}
+
+	var cmd tea.Cmd
+
+	switch q.Type {
+	case question.Text:
+		if key.String() == "enter" {
+			return f.answer(f.input.Value())
+		}
+		f.input, cmd = f.input.Update(msg)
+
+	case question.Select:
+		if key.String() == "enter" && len(q.Options) > 0 {
+			return f.answer(q.Options[f.choice.Index()].Value)
+		}
+		f.choice, cmd = f.choice.Update(msg)
+
+	case question.MultiSelect:
+		if key.String() == "enter" {
+			return f.answer(f.list.Selected())
+		}
+		f.list, cmd = f.list.Update(msg)
+
+	case question.Confirm:
+		if val, ok := f.confirm.Answer(key); ok {
+			return f.answer(val)
+		}
+		f.confirm, cmd = f.confirm.Update(msg)
+	}
+
+	return f, cmd
+}
+
+// answer validates and stores the current answer, then moves to the next
+// visible question or submits.
+func (f Form) answer(val any) (Form, tea.Cmd) {
+	q := f.current()
+
+	if err := q.CheckAnswer(val); err != nil {
+		f.errs[q.ID] = err.Error()
+		if q.Type == question.Text {
+			f.input.SetError(err.Error())
+		}
+		return f, nil
+	}
+	f.answers[q.ID] = val
+	delete(f.errs, q.ID)
+
+	next := f.next(f.index)
+	if next < 0 {
+		f.state = Submitted
+		return f, nil
+	}
+	f.index = next
+	return f, f.prepare()
+}
+
+func (f Form) View() string {
+	if f.state != Asking {
+		return ""
+	}
+
+	var b strings.Builder
+
+	q := f.current()
+	pos, total := f.position()
+
+	if f.title != "" {
+		b.WriteString(f.title + "\n\n")
+	}
+	b.WriteString(ui.Header(q.Label, pos, total))
+	if q.Help != "" {
+		b.WriteString(q.Help + "\n\n")
+	}
+
+	switch q.Type {
+	case question.Text:
+		b.WriteString(f.input.View())
+	case question.Select:
+		b.WriteString(f.choice.View())
+	case question.MultiSelect:
+		b.WriteString(f.list.View())
+	case question.Confirm:
+		b.WriteString(f.confirm.View())
+	}
+
+	if msg := f.errs[q.ID]; msg != "" && q.Type != question.Text {
+		b.WriteString(ui.ErrorLine(msg))
+	}
+
+	b.WriteString(helpFor(q.Type))
+
+	return b.String()
+}
+
+func helpFor(t question.Type) string {
+	switch t {
+	case question.Select:
+		return ui.Help(ui.SelectKeys, "[enter] Choose", "[esc] Back", "[ctrl+c] Quit")
+	case question.MultiSelect:
+		return ui.Help(ui.ChecklistKeys, "[enter] Next", "[esc] Back", "[ctrl+c] Quit")
+	case question.Confirm:
+		return ui.Help(ui.ConfirmKeys, "[esc] Back", "[ctrl+c] Quit")
+	}
+	return ui.Help("[enter] Next", "[esc] Back", "[ctrl+c] Quit")
+}
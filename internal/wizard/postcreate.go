@@ -0,0 +1,195 @@
+package wizard
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/nav"
+	"github.com/ezeqielle/pcli/internal/postplugin"
+	"github.com/ezeqielle/pcli/internal/ui"
+)
+
+type postCreateStep int
+
+const (
+	stepForm postCreateStep = iota
+	stepRollback
+	stepDone
+)
+
+// PostCreate is the wizard of a declarative post-create plugin: its
+// questions in a Form, then what Apply did. When Apply fails, or ctrl+c is
+// pressed after pcli created files, it offers to roll back the run's
+// journal, found through fs.
+type PostCreate struct {
+	step postCreateStep
+
+	plugin      postplugin.Declarative
+	fs          fsys.FS
+	title       string
+	projectPath string
+	projectType string
+
+	form    Form
+	errMsg  string
+	summary []string
+
+	// rollback is shown in stepRollback; rollbackFrom is the step it
+	// resumes.
+	rollback     Rollback
+	rollbackFrom postCreateStep
+}
+
+// NewPostCreate asks p's questions for the project; title names the plugin
+// in headers. An error from Questions is shown on the result screen.
+func NewPostCreate(p postplugin.Declarative, fs fsys.FS, title, projectPath, projectType string) PostCreate {
+	m := PostCreate{
+		step:        stepForm,
+		plugin:      p,
+		fs:          fs,
+		title:       "Post-create – " + title,
+		projectPath: projectPath,
+		projectType: projectType,
+	}
+
+	qs, err := p.Questions(projectPath, projectType)
+	if err != nil {
+		m.errMsg = err.Error()
+		m.step = stepDone
+		return m
+	}
+	m.form = NewForm(m.title+"\n\nProject: "+projectPath, qs, nil)
+	return m
+}
+
+func (m PostCreate) Init() tea.Cmd {
+	if m.step != stepForm {
+		return nil
+	}
+	return m.form.Init()
+}
+
+func (m PostCreate) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch m.step {
+		case stepRollback:
+			return m.updateRollback(key)
+
+		case stepDone:
+			switch key.String() {
+			case "ctrl+c":
+				return m, tea.Quit
+			case "esc":
+				return m, nav.Back
+			}
+			// any other key moves on to the next post-create plugin, unless
+			// the project was just rolled back
+			if m.rollback.State() == RolledBack {
+				return m, postplugin.Abort
+			}
+			return m, postplugin.Finish
+		}
+
+		if key.String() == "ctrl+c" {
+			return m.cancel()
+		}
+	}
+
+	if m.step != stepForm {
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.form, cmd = m.form.Update(msg)
+
+	switch m.form.State() {
+	case Submitted:
+		return m.apply()
+
+	case Cancelled:
+		m.form = m.form.Resume()
+		return m, nav.Back
+	}
+
+	return m, cmd
+}
+
+func (m PostCreate) updateRollback(key tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.rollback, cmd = m.rollback.Update(key)
+
+	switch m.rollback.State() {
+	case RolledBack:
+		m.summary = m.rollback.Summary()
+		m.errMsg = ""
+		if err := m.rollback.Err(); err != nil {
+			m.errMsg = "rollback incomplete: " + err.Error()
+		}
+		m.step = stepDone
+
+	case Kept:
+		m.step = stepDone
+
+	case Resumed:
+		m.step = m.rollbackFrom
+	}
+	return m, cmd
+}
+
+// apply runs the plugin with the form's answers and moves to the result or
+// rollback step.
+func (m PostCreate) apply() (tea.Model, tea.Cmd) {
+	summary, err := m.plugin.Apply(m.projectPath, m.projectType, m.form.Answers())
+	m.summary = summary
+	m.step = stepDone
+	if err == nil {
+		return m, nil
+	}
+
+	m.errMsg = err.Error()
+	if CanRollback(m.fs) {
+		m.rollback = NewRollback(m.fs, m.title, "Applying post-create items failed: "+err.Error())
+		m.step = stepRollback
+	}
+	return m, nil
+}
+
+// cancel offers to roll back the run when quitting after pcli has already
+// created files; otherwise it quits right away.
+func (m PostCreate) cancel() (tea.Model, tea.Cmd) {
+	if !CanRollback(m.fs) {
+		return m, tea.Quit
+	}
+	m.rollback = CancelRollback(m.fs, m.title)
+	m.rollbackFrom = m.step
+	m.step = stepRollback
+	return m, nil
+}
+
+func (m PostCreate) View() string {
+	switch m.step {
+	case stepForm:
+		return m.form.View()
+	case stepRollback:
+		return m.rollback.View()
+	}
+
+	var b strings.Builder
+
+	b.WriteString(ui.Header(m.title, 0, 0))
+	b.WriteString("Project: " + m.projectPath + "\n\n")
+
+	for _, line := range m.summary {
+		b.WriteString("- " + line + "\n")
+	}
+
+	if m.errMsg != "" {
+		b.WriteString(ui.ErrorLine(m.errMsg))
+	}
+
+	b.WriteString(ui.Help("[esc] Back", "[any key] Continue"))
+
+	return b.String()
+}
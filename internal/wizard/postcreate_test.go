@@ -0,0 +1,63 @@
+package wizard
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/journal"
+	"github.com/ezeqielle/pcli/internal/postplugin"
+	"github.com/ezeqielle/pcli/internal/question"
+)
+
+// failingPlugin writes a file, then fails.
+type failingPlugin struct {
+	fs fsys.FS
+}
+
+func (p failingPlugin) Questions(projectPath, projectType string) ([]question.Question, error) {
+	return nil, nil
+}
+
+func (p failingPlugin) Apply(projectPath, projectType string, answers question.Answers) ([]string, error) {
+	if err := p.fs.WriteFile("/p/Dockerfile", nil, 0o644); err != nil {
+		return nil, err
+	}
+	return nil, errors.New("compose.yaml: permission denied")
+}
+
+func TestPostCreateAfterRollback(t *testing.T) {
+	tests := []struct {
+		name   string
+		answer string
+		want   tea.Msg
+	}{
+		{"rolled back", "y", postplugin.AbortMsg{}},
+		{"kept", "n", postplugin.DoneMsg{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mem := fsys.NewMemory()
+			if err := mem.MkdirAll("/p", 0o755); err != nil {
+				t.Fatal(err)
+			}
+			fs := journal.New(mem).FS()
+
+			var m tea.Model = NewPostCreate(failingPlugin{fs: fs}, fs, "Test", "/p", "go")
+			// the form has no question: the first update applies
+			m, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+			if m.(PostCreate).step != stepRollback {
+				t.Fatalf("step = %v, want the rollback question", m.(PostCreate).step)
+			}
+
+			m, _ = m.Update(key(tt.answer))
+			_, cmd := m.Update(key("x"))
+			if cmd == nil || cmd() != tt.want {
+				t.Errorf("done screen sent %v, want %T", cmd, tt.want)
+			}
+		})
+	}
+}
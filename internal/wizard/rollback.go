@@ -0,0 +1,143 @@
+package wizard
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/journal"
+	"github.com/ezeqielle/pcli/internal/ui"
+)
+
+// RollbackState tells the owner of a Rollback what the user chose.
+type RollbackState int
+
+const (
+	// Confirming means the question is on screen.
+	Confirming RollbackState = iota
+	// RolledBack means every journaled change was undone; Summary and Err
+	// tell how it went.
+	RolledBack
+	// Kept means the changes stay. After a cancel, Update also quits.
+	Kept
+	// Resumed means esc closed the question after a cancel: the owner goes
+	// back to the step it was on.
+	Resumed
+)
+
+// Rollback asks whether to undo everything pcli did in this run, listing
+// the journal of fs. Wizards show it when a step fails (NewRollback) or
+// when ctrl+c is pressed after pcli wrote something (CancelRollback).
+type Rollback struct {
+	fs     fsys.FS
+	title  string
+	reason string
+	// cancelled is set when ctrl+c opened the question: declining quits
+	// and esc resumes the wizard.
+	cancelled bool
+
+	confirm ui.Confirm
+	state   RollbackState
+	summary []string
+	err     error
+}
+
+// CanRollback reports whether fs is journaled and holds changes to undo.
+func CanRollback(fs fsys.FS) bool {
+	j := journal.Of(fs)
+	return j != nil && j.HasChanges()
+}
+
+// NewRollback offers to undo the run after a failure described by reason.
+// Declining or esc keeps the changes.
+func NewRollback(fs fsys.FS, title, reason string) Rollback {
+	return Rollback{
+		fs:      fs,
+		title:   title,
+		reason:  reason,
+		confirm: ui.NewConfirm("Roll back everything pcli created?", true),
+	}
+}
+
+// CancelRollback offers to undo the run when ctrl+c is pressed. Declining
+// quits and esc resumes the wizard.
+func CancelRollback(fs fsys.FS, title string) Rollback {
+	r := NewRollback(fs, title, "Cancelled.")
+	r.cancelled = true
+	return r
+}
+
+func (r Rollback) State() RollbackState {
+	return r.state
+}
+
+// Summary describes what the rollback undid.
+func (r Rollback) Summary() []string {
+	return r.summary
+}
+
+// Err is the error of an incomplete rollback.
+func (r Rollback) Err() error {
+	return r.err
+}
+
+func (r Rollback) Update(msg tea.Msg) (Rollback, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok || r.state != Confirming {
+		return r, nil
+	}
+
+	if yes, ok := r.confirm.Answer(key); ok {
+		if !yes {
+			r.state = Kept
+			if r.cancelled {
+				return r, tea.Quit
+			}
+			return r, nil
+		}
+		summary, err := journal.Of(r.fs).Rollback()
+		r.summary = append(summary, "Rolled back every change pcli made in this run.")
+		r.err = err
+		r.state = RolledBack
+		return r, nil
+	}
+
+	switch key.String() {
+	case "esc":
+		r.state = Kept
+		if r.cancelled {
+			r.state = Resumed
+		}
+		return r, nil
+
+	case "ctrl+c":
+		return r, tea.Quit
+	}
+
+	var cmd tea.Cmd
+	r.confirm, cmd = r.confirm.Update(key)
+	return r, cmd
+}
+
+func (r Rollback) View() string {
+	var b strings.Builder
+
+	b.WriteString(r.title + " – Roll back\n\n")
+	b.WriteString(r.reason + "\n\n")
+	b.WriteString("pcli made these changes in this run:\n\n")
+	if j := journal.Of(r.fs); j != nil {
+		for _, e := range j.Entries() {
+			b.WriteString("  " + e.String() + "\n")
+		}
+	}
+	b.WriteString("\n" + r.confirm.View())
+
+	if r.cancelled {
+		b.WriteString(ui.Help("[y] Roll back", "[n] Keep and quit", "[esc] Continue", "[ctrl+c] Quit"))
+	} else {
+		b.WriteString(ui.Help("[y] Roll back", "[n] Keep", "[esc] Keep", "[ctrl+c] Quit"))
+	}
+
+	return b.String()
+}
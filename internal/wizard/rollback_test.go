@@ -0,0 +1,81 @@
+package wizard
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ezeqielle/pcli/internal/fsys"
+	"github.com/ezeqielle/pcli/internal/journal"
+)
+
+func key(s string) tea.KeyMsg {
+	switch s {
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case "ctrl+c":
+		return tea.KeyMsg{Type: tea.KeyCtrlC}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+// journaled returns an FS whose journal holds one created file.
+func journaled(t *testing.T) (fsys.FS, *fsys.Memory) {
+	t.Helper()
+
+	mem := fsys.NewMemory()
+	fs := journal.New(mem).FS()
+	if err := fs.WriteFile("/README.md", []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return fs, mem
+}
+
+func TestRollback(t *testing.T) {
+	tests := []struct {
+		name      string
+		cancelled bool
+		key       string
+		want      RollbackState
+		quit      bool
+		removed   bool
+	}{
+		{"yes", false, "y", RolledBack, false, true},
+		{"no", false, "n", Kept, false, false},
+		{"esc keeps", false, "esc", Kept, false, false},
+		{"ctrl+c", false, "ctrl+c", Confirming, true, false},
+		{"cancel, yes", true, "y", RolledBack, false, true},
+		{"cancel, no quits", true, "n", Kept, true, false},
+		{"cancel, esc resumes", true, "esc", Resumed, false, false},
+		{"other key", false, "x", Confirming, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs, mem := journaled(t)
+			if !CanRollback(fs) {
+				t.Fatal("CanRollback = false with a journaled change")
+			}
+
+			r := NewRollback(fs, "Test", "Applying failed.")
+			if tt.cancelled {
+				r = CancelRollback(fs, "Test")
+			}
+			r, cmd := r.Update(key(tt.key))
+
+			if r.State() != tt.want {
+				t.Errorf("State = %v, want %v", r.State(), tt.want)
+			}
+			if quit := cmd != nil && cmd() == tea.Quit(); quit != tt.quit {
+				t.Errorf("quit = %v, want %v", quit, tt.quit)
+			}
+			if removed := !fsys.Exists(mem, "/README.md"); removed != tt.removed {
+				t.Errorf("removed = %v, want %v", removed, tt.removed)
+			}
+		})
+	}
+
+	if CanRollback(fsys.NewMemory()) {
+		t.Error("CanRollback = true without a journal")
+	}
+}